@@ -0,0 +1,418 @@
+//go:build llvm
+// +build llvm
+
+package main
+
+import (
+	"fmt"
+	"novum-lang/llvm/bindings/go/llvm"
+	"strings"
+)
+
+// Externs follow the C calling convention of the target, which doesn't pass
+// structs (and the enums and arrays built like them) as LLVM aggregates. A
+// small struct is split over registers by the kind of its fields, a large
+// one is copied to memory and passed by address. Each extern is declared
+// with the signature a C compiler gives the same C function, and its calls
+// convert the values in between.
+
+// abiKind is how an argument or a result of an extern is passed.
+type abiKind int
+
+const (
+	// abiDirect passes the value as its LLVM type
+	abiDirect abiKind = iota
+	// abiCoerce passes the bytes of the value as the parts, one parameter
+	// each
+	abiCoerce
+	// abiIndirect passes the address of a copy of the value. A result is
+	// written by the callee to memory the caller gives (sret).
+	abiIndirect
+)
+
+type abiValue struct {
+	kind abiKind
+	typ  llvm.Type
+	// parts are the registers holding a coerced value
+	parts []llvm.Type
+	// memory is big enough for both the value and its parts
+	memory llvm.Type
+	// byval makes the callee find an indirect argument on the stack
+	byval bool
+	// promoted is the type a variadic argument is widened to, signed telling
+	// how an integer is extended
+	promoted llvm.Type
+	signed   bool
+}
+
+// externABI is the C signature of an extern. The arguments after the first
+// fixed ones are variadic.
+type externABI struct {
+	fcType   llvm.Type
+	ret      abiValue
+	args     []abiValue
+	fixed    int
+	variadic bool
+}
+
+// externABIs holds the signature of each extern by name.
+var externABIs map[string]*externABI
+
+// variadicExterns gives the number of fixed arguments of the C library
+// functions taking a variable number of them. An extern declares the types
+// of the arguments it is called with, and the ones after the fixed arguments
+// are passed as C passes variadic arguments.
+var variadicExterns = map[string]int{
+	"printf":   1,
+	"fprintf":  2,
+	"dprintf":  2,
+	"sprintf":  2,
+	"snprintf": 3,
+	"scanf":    1,
+	"fscanf":   2,
+	"sscanf":   2,
+}
+
+// declareExtern adds the declaration of an extern to the module. It fails
+// when the target passes structs in a way that isn't implemented.
+func declareExtern(p *PrototypeAST) (llvm.Value, error) {
+	triple := module.Target()
+	if triple == "" {
+		triple = llvm.DefaultTargetTriple()
+	}
+	targetData := llvm.NewTargetData(module.DataLayout())
+	defer targetData.Dispose()
+
+	abi := &externABI{ret: abiValue{typ: llvmType(p.ReturnType)}, fixed: len(p.Args)}
+	for _, a := range p.Args {
+		abi.args = append(abi.args, abiValue{typ: llvmType(a.ArgType)})
+	}
+	if fixed, ok := variadicExterns[p.Name]; ok && fixed <= len(p.Args) {
+		abi.fixed, abi.variadic = fixed, true
+		for i := fixed; i < len(p.Args); i++ {
+			abi.args[i].promote(p.Args[i].ArgType)
+		}
+	}
+
+	arch := triple[:strings.Index(triple+"-", "-")]
+	switch {
+	case arch == "x86_64" && (strings.Contains(triple, "windows") || strings.Contains(triple, "mingw") || strings.Contains(triple, "cygwin")):
+		classifyWin64(abi, targetData)
+	case arch == "x86_64":
+		classifySysV(abi, targetData)
+	case arch == "aarch64" || arch == "arm64":
+		classifyAAPCS64(abi, targetData)
+	default:
+		for _, v := range append(abi.args, abi.ret) {
+			if isStruct(v.typ) {
+				return llvm.Value{}, fmt.Errorf(`Extern "%s" can't take or return a struct on target %s.`, p.Name, triple)
+			}
+		}
+	}
+
+	var params []llvm.Type
+	ret := abi.ret.typ
+	switch abi.ret.kind {
+	case abiCoerce:
+		ret = partsType(abi.ret.parts)
+	case abiIndirect:
+		ret = ctx.VoidType()
+		params = append(params, llvm.PointerType(abi.ret.typ, 0))
+	}
+	for _, arg := range abi.args[:abi.fixed] {
+		switch arg.kind {
+		case abiDirect:
+			params = append(params, arg.typ)
+		case abiCoerce:
+			params = append(params, arg.parts...)
+		case abiIndirect:
+			params = append(params, llvm.PointerType(arg.typ, 0))
+		}
+	}
+
+	abi.fcType = llvm.FunctionType(ret, params, abi.variadic)
+	fc := llvm.AddFunction(module, p.Name, abi.fcType)
+	abi.addAttributes(fc.AddAttributeAtIndex, abi.args[:abi.fixed])
+	externABIs[p.Name] = abi
+	return fc, nil
+}
+
+// call calls the extern with the arguments as the rest of the program passes
+// them and returns its result the same way.
+func (abi *externABI) call(fc llvm.Value, args []llvm.Value) llvm.Value {
+	caller := builder.GetInsertBlock().Parent()
+
+	var params []llvm.Value
+	var result llvm.Value
+	if abi.ret.kind == abiIndirect {
+		result = createEntryBlockAlloca(caller, abi.ret.typ, "sret")
+		params = append(params, result)
+	}
+	for i, arg := range abi.args {
+		switch arg.kind {
+		case abiDirect:
+			params = append(params, arg.widen(args[i]))
+		case abiCoerce:
+			memory := createEntryBlockAlloca(caller, arg.memory, "coerce")
+			builder.CreateStore(args[i], builder.CreateBitCast(memory, llvm.PointerType(arg.typ, 0), ""))
+			parts := builder.CreateBitCast(memory, llvm.PointerType(partsType(arg.parts), 0), "")
+			if len(arg.parts) == 1 {
+				params = append(params, builder.CreateLoad(parts, "coerce"))
+				break
+			}
+			for j := range arg.parts {
+				params = append(params, builder.CreateLoad(builder.CreateStructGEP(parts, j, ""), "coerce"))
+			}
+		case abiIndirect:
+			byref := createEntryBlockAlloca(caller, arg.typ, "byref")
+			builder.CreateStore(args[i], byref)
+			params = append(params, byref)
+		}
+	}
+
+	call := builder.CreateCall(fc, params, "")
+	abi.addAttributes(call.AddCallSiteAttribute, abi.args)
+
+	switch abi.ret.kind {
+	case abiCoerce:
+		memory := createEntryBlockAlloca(caller, abi.ret.memory, "coerce")
+		builder.CreateStore(call, builder.CreateBitCast(memory, llvm.PointerType(call.Type(), 0), ""))
+		return builder.CreateLoad(builder.CreateBitCast(memory, llvm.PointerType(abi.ret.typ, 0), ""), "")
+	case abiIndirect:
+		return builder.CreateLoad(result, "")
+	}
+	return call
+}
+
+// addAttributes marks the memory of indirect results and the arguments
+// copied to the stack, on the declaration for the fixed arguments and on each
+// call for all of them.
+func (abi *externABI) addAttributes(add func(i int, a llvm.Attribute), args []abiValue) {
+	index := 1
+	if abi.ret.kind == abiIndirect {
+		add(index, ctx.CreateTypeAttribute(llvm.AttributeKindID("sret"), abi.ret.typ))
+		index++
+	}
+	for _, arg := range args {
+		if arg.kind == abiIndirect && arg.byval {
+			add(index, ctx.CreateTypeAttribute(llvm.AttributeKindID("byval"), arg.typ))
+		}
+		if arg.kind == abiCoerce {
+			index += len(arg.parts)
+		} else {
+			index++
+		}
+	}
+}
+
+// classifySysV follows the System V ABI of x86-64 Unix systems. A struct of
+// up to 16 bytes is split into eightbytes, each passed in an SSE register
+// when it only holds floats and in a general register otherwise. A larger
+// struct, or one the registers left can't take whole, is passed in memory.
+func classifySysV(abi *externABI, targetData llvm.TargetData) {
+	ints, floats := 6, 8
+	if isStruct(abi.ret.typ) {
+		if parts := eightbytes(abi.ret.typ, targetData); parts != nil {
+			abi.ret.coerce(parts, targetData)
+		} else {
+			abi.ret.kind = abiIndirect
+			ints--
+		}
+	}
+
+	for i := range abi.args {
+		arg := &abi.args[i]
+		if !isStruct(arg.typ) {
+			if isFloat(arg.typ) {
+				floats--
+			} else {
+				ints--
+			}
+			continue
+		}
+
+		parts := eightbytes(arg.typ, targetData)
+		needFloats := 0
+		for _, part := range parts {
+			if isFloat(part) {
+				needFloats++
+			}
+		}
+		needInts := len(parts) - needFloats
+		if parts == nil || needInts > ints || needFloats > floats {
+			arg.kind, arg.byval = abiIndirect, true
+			continue
+		}
+		arg.coerce(parts, targetData)
+		ints -= needInts
+		floats -= needFloats
+	}
+}
+
+// eightbytes returns the registers holding a struct of up to 16 bytes, nil
+// for a larger one.
+func eightbytes(t llvm.Type, targetData llvm.TargetData) []llvm.Type {
+	size := targetData.TypeAllocSize(t)
+	if size > 16 {
+		return nil
+	}
+
+	count := (size + 7) / 8
+	floats := make([]bool, count)
+	ends := make([]uint64, count)
+	for i := range floats {
+		floats[i] = true
+	}
+	eachScalar(t, 0, targetData, func(scalar llvm.Type, offset uint64) {
+		i := offset / 8
+		floats[i] = floats[i] && isFloat(scalar)
+		if end := offset + targetData.TypeAllocSize(scalar) - 8*i; end > ends[i] {
+			ends[i] = end
+		}
+	})
+
+	parts := make([]llvm.Type, count)
+	for i := range parts {
+		switch {
+		case floats[i] && ends[i] <= 4:
+			parts[i] = ctx.FloatType()
+		case floats[i]:
+			// Two floats share the register like the halves of a double
+			parts[i] = ctx.DoubleType()
+		case size-8*uint64(i) < 8:
+			parts[i] = ctx.IntType(int(size-8*uint64(i)) * 8)
+		default:
+			parts[i] = ctx.Int64Type()
+		}
+	}
+	return parts
+}
+
+// classifyWin64 follows the Windows x64 calling convention, which passes a
+// struct of 1, 2, 4 or 8 bytes as an integer and any other one by address.
+func classifyWin64(abi *externABI, targetData llvm.TargetData) {
+	classify := func(v *abiValue) {
+		if !isStruct(v.typ) {
+			return
+		}
+		switch size := targetData.TypeAllocSize(v.typ); size {
+		case 1, 2, 4, 8:
+			v.coerce([]llvm.Type{ctx.IntType(int(size) * 8)}, targetData)
+		default:
+			v.kind = abiIndirect
+		}
+	}
+
+	classify(&abi.ret)
+	for i := range abi.args {
+		classify(&abi.args[i])
+	}
+}
+
+// classifyAAPCS64 follows the procedure call standard of AArch64. A struct
+// of one to four fields of the same float type goes in consecutive SIMD
+// registers. Otherwise one of up to 16 bytes goes in general registers and
+// a larger one is passed by address.
+func classifyAAPCS64(abi *externABI, targetData llvm.TargetData) {
+	classify := func(v *abiValue) {
+		if !isStruct(v.typ) {
+			return
+		}
+
+		var fields []llvm.Type
+		eachScalar(v.typ, 0, targetData, func(scalar llvm.Type, _ uint64) {
+			fields = append(fields, scalar)
+		})
+		homogeneous := len(fields) >= 1 && len(fields) <= 4
+		for _, field := range fields {
+			homogeneous = homogeneous && isFloat(field) && field.TypeKind() == fields[0].TypeKind()
+		}
+
+		switch size := targetData.TypeAllocSize(v.typ); {
+		case homogeneous:
+			v.coerce([]llvm.Type{llvm.ArrayType(fields[0], len(fields))}, targetData)
+		case size <= 8:
+			v.coerce([]llvm.Type{ctx.Int64Type()}, targetData)
+		case size <= 16:
+			v.coerce([]llvm.Type{llvm.ArrayType(ctx.Int64Type(), 2)}, targetData)
+		default:
+			v.kind = abiIndirect
+		}
+	}
+
+	classify(&abi.ret)
+	for i := range abi.args {
+		classify(&abi.args[i])
+	}
+}
+
+// promote applies C's default argument promotions to a variadic argument of
+// the type: f32 is passed as a double, and narrower integers than int as an
+// int.
+func (v *abiValue) promote(typ string) {
+	info, ok := numberTypes[typ]
+	switch {
+	case ok && info.float && info.bits == 32:
+		v.promoted = ctx.DoubleType()
+	case ok && !info.float && info.bits < 32:
+		v.promoted, v.signed = ctx.Int32Type(), info.signed
+	case typ == LitChar || typ == LitBool:
+		v.promoted = ctx.Int32Type()
+	}
+}
+
+// widen converts a variadic argument to its promoted type.
+func (v *abiValue) widen(value llvm.Value) llvm.Value {
+	switch {
+	case v.promoted.IsNil():
+		return value
+	case isFloat(v.promoted):
+		return builder.CreateFPExt(value, v.promoted, "promoted")
+	case v.signed:
+		return builder.CreateSExt(value, v.promoted, "promoted")
+	}
+	return builder.CreateZExt(value, v.promoted, "promoted")
+}
+
+// coerce passes the value as the parts, through memory holding either.
+func (v *abiValue) coerce(parts []llvm.Type, targetData llvm.TargetData) {
+	v.kind = abiCoerce
+	v.parts = parts
+	size := targetData.TypeAllocSize(v.typ)
+	if partsSize := targetData.TypeAllocSize(partsType(parts)); partsSize > size {
+		size = partsSize
+	}
+	v.memory = llvm.ArrayType(ctx.Int64Type(), int(size+7)/8)
+}
+
+// partsType is the type of the parts together: the part itself when there is
+// one, or a struct of them, which is how a coerced result is returned.
+func partsType(parts []llvm.Type) llvm.Type {
+	switch len(parts) {
+	case 0:
+		return ctx.VoidType()
+	case 1:
+		return parts[0]
+	}
+	return ctx.StructType(parts, false)
+}
+
+// eachScalar calls f with every field of the nested structs in t that isn't a
+// struct itself and its offset from the start of t.
+func eachScalar(t llvm.Type, offset uint64, targetData llvm.TargetData, f func(scalar llvm.Type, offset uint64)) {
+	if !isStruct(t) {
+		f(t, offset)
+		return
+	}
+	for i, field := range t.StructElementTypes() {
+		eachScalar(field, offset+targetData.ElementOffset(t, i), targetData, f)
+	}
+}
+
+func isStruct(t llvm.Type) bool {
+	return t.TypeKind() == llvm.StructTypeKind
+}
+
+func isFloat(t llvm.Type) bool {
+	return t.TypeKind() == llvm.FloatTypeKind || t.TypeKind() == llvm.DoubleTypeKind
+}
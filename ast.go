@@ -1,8 +1,6 @@
 package main
 
-import (
-	"novum-lang/llvm/bindings/go/llvm"
-)
+import "strings"
 
 const (
 	astFunction kind = iota
@@ -19,17 +17,35 @@ const (
 	astReturn
 	astLoop
 	astUnary
+	astVarDecl
+	astAssign
+	astBreak
+	astContinue
+	astRange
+	astChar
+	astArray
+	astIndex
+	astStruct
+	astStructLit
+	astField
+	astEnum
+	astVariant
+	astMatch
+	astAtom
+	astCast
 )
 
 type AST interface {
 	Position() Pos
 	Kind() kind
-	codegen() llvm.Value
+	codegenAST
 }
 
+// Pos is a location in a source file. Lines and columns start at 1.
 type Pos struct {
-	row int
-	col int
+	file string
+	row  int
+	col  int
 }
 type kind int
 
@@ -41,38 +57,99 @@ func (k kind) Kind() kind {
 	return k
 }
 
+// exprType holds the type the checker inferred for an expression.
+type exprType struct {
+	typ string
+}
+
+func (e *exprType) Type() string {
+	return e.typ
+}
+
+func (e *exprType) setType(typ string) {
+	e.typ = typ
+}
+
+// typeOf returns the checked type of an expression, or "" for statements.
+func typeOf(ast AST) string {
+	if expr, ok := ast.(interface{ Type() string }); ok {
+		return expr.Type()
+	}
+	return ""
+}
+
 type NumberLiteralAST struct {
 	Pos
 	kind
+	exprType
 	Value float64
 }
 
+// BinaryAST is a binary operation. Func names the function implementing a
+// user defined operator and is empty for the built-in ones.
 type BinaryAST struct {
 	Pos
 	kind
+	exprType
 	Op       string
 	Lhs, Rhs AST
+	Func     string
 }
 
 type UnaryAST struct {
 	Pos
 	kind
+	exprType
 	Operator int
 	Operand  AST
+	Func     string
 }
 
 type BoolAST struct {
 	Pos
 	kind
+	exprType
 	Value int
 }
 
+// CharAST is a character literal, a single byte.
+type CharAST struct {
+	Pos
+	kind
+	exprType
+	Value byte
+}
+
+// AtomAST is an atom like :ok. Value is its name without the colon.
+type AtomAST struct {
+	Pos
+	kind
+	exprType
+	Value string
+}
+
+// CastAST converts a number to another numeric type, or a char to and from
+// an integer: 'value as To'. The checker adds implicit ones where a number
+// widens to the expected type.
+type CastAST struct {
+	Pos
+	kind
+	exprType
+	Value    AST
+	To       string
+	ToPos    Pos
+	Implicit bool
+}
+
 type StringAST struct {
 	Pos
 	kind
+	exprType
 	Value string
 }
 
+// VariableAST is a use of a variable. VarType and Mutable are filled in by
+// the checker from the declaration the name resolves to.
 type VariableAST struct {
 	Pos
 	kind
@@ -81,6 +158,148 @@ type VariableAST struct {
 	Mutable bool
 }
 
+func (v *VariableAST) Type() string {
+	return v.VarType
+}
+
+func (v *VariableAST) setType(typ string) {
+	v.VarType = typ
+}
+
+// VarDeclAST declares a local with 'let' (immutable) or 'var' (mutable).
+// VarType is empty when the type is left to be inferred from Value.
+type VarDeclAST struct {
+	Pos
+	kind
+	Name    string
+	Mutable bool
+	VarType string
+	TypePos Pos
+	Value   AST
+}
+
+// AssignAST stores Value in a variable or an array element.
+type AssignAST struct {
+	Pos
+	kind
+	Target AST // *VariableAST, *IndexAST or *FieldAST
+	Value  AST
+}
+
+// ArrayAST is an array literal like [1, 2, 3].
+type ArrayAST struct {
+	Pos
+	kind
+	exprType
+	Elements []AST
+}
+
+// StructAST declares a struct type. The fields are laid out in their order,
+// like in C.
+type StructAST struct {
+	Pos
+	kind
+	Name   string
+	Fields []ArgsPrototype
+}
+
+// StructLitAST is a struct literal like Point { x: 1.0, y: 2.0 }.
+type StructLitAST struct {
+	Pos
+	kind
+	exprType
+	Name   string
+	Fields []FieldInit
+}
+
+// FieldInit is the value of one field in a struct literal. Index is the
+// position of the field in the struct, filled in by the checker.
+type FieldInit struct {
+	Pos
+	Name  string
+	Value AST
+	Index int
+}
+
+// EnumAST declares an enum. A value of it is one of the variants, together
+// with the payload of that variant.
+type EnumAST struct {
+	Pos
+	kind
+	Name     string
+	Variants []Variant
+}
+
+// Variant is one variant of an enum and the types of its payload.
+type Variant struct {
+	Pos
+	Name       string
+	Payload    []string
+	PayloadPos []Pos
+}
+
+// VariantAST makes a value of an enum, like Shape.Circle(1.0). Tag is the
+// position of the variant in the enum, filled in by the checker.
+type VariantAST struct {
+	Pos
+	kind
+	exprType
+	Enum    string
+	Variant string
+	Args    []AST
+	Tag     int
+}
+
+// MatchAST runs the first arm whose pattern matches the value. Arms whose
+// body is an expression give the match their value, arms with a block make
+// it a statement.
+type MatchAST struct {
+	Pos
+	kind
+	exprType
+	Value AST
+	Arms  []MatchArm
+}
+
+// MatchArm is one 'pattern => body' arm of a match. The pattern is either a
+// variant, which binds the names to its payload ('_' skips one), a literal,
+// or '_' matching anything when Variant and Literal are both empty. The body
+// is either the expression Value or Block.
+type MatchArm struct {
+	Pos
+	Enum     string
+	Variant  string
+	Bindings []*VariableAST
+	Literal  AST
+	Value    AST
+	Block    *BlockAST
+	Tag      int
+}
+
+func (a *MatchArm) wildcard() bool {
+	return a.Variant == "" && a.Literal == nil
+}
+
+// FieldAST is s.Name. Pos is the position of the name and Index the position
+// of the field in the struct, filled in by the checker.
+type FieldAST struct {
+	Pos
+	kind
+	exprType
+	Struct AST
+	Name   string
+	Index  int
+}
+
+// IndexAST is a[i]. Pos is the position of the '['.
+type IndexAST struct {
+	Pos
+	kind
+	exprType
+	Array AST
+	Index AST
+}
+
 type ElseIfAST struct {
 	Pos
 	kind
@@ -97,6 +316,19 @@ type IfElseAST struct {
 	ElseIfBody []ElseIfAST
 }
 
+// RangeAST counts from Start up to End, or down to it with a negative Step.
+// End itself is only included in ranges written with '..='. Step is nil when
+// it isn't given, meaning 1. Ranges can only be iterated by loops.
+type RangeAST struct {
+	Pos
+	kind
+	Start, End AST
+	Step       AST
+	Inclusive  bool
+}
+
+// LoopAST is 'for cond' or a for-in loop over Condition. IndexVar is empty
+// in 'for element in ...' loops.
 type LoopAST struct {
 	Pos
 	kind
@@ -105,19 +337,34 @@ type LoopAST struct {
 	IndexVar   string
 	ElementVar string
 	Body       BlockAST
+	Label      string
+}
+
+// BranchAST is a break or continue. An empty Label means the innermost loop.
+type BranchAST struct {
+	Pos
+	kind
+	Label string
 }
 
 type CallAST struct {
 	Pos
 	kind
+	exprType
 	Callee string
 	args   []AST
+	// Method calls pass the receiver as the first argument. The checker
+	// replaces Callee with the mangled name of the method.
+	Method bool
 }
 
+// ReturnAST returns Body, or nothing when it is nil. Implicit marks the return
+// added at the end of functions that return nothing.
 type ReturnAST struct {
 	Pos
 	kind
-	Body AST
+	Body     AST
+	Implicit bool
 }
 
 type BlockAST struct {
@@ -127,19 +374,58 @@ type BlockAST struct {
 }
 
 type ArgsPrototype struct {
+	Pos
 	Name    string
 	ArgType string
+	TypePos Pos
 }
 
 type PrototypeAST struct {
 	Pos
 	kind
-	Name       string
-	Args       []ArgsPrototype
-	IsOperator bool
-	IsUnaryOp  bool
-	Precedence int
-	ReturnType string
+	Name          string
+	Args          []ArgsPrototype
+	IsOperator    bool
+	IsBinaryOp    bool
+	Precedence    int
+	ReturnType    string
+	ReturnTypePos Pos
+	// Receiver is the type a method is defined on. Methods take the receiver
+	// as their first argument and are named "Type.name".
+	Receiver string
+}
+
+// methodName mangles the name of a method defined on typ. Identifiers can't
+// contain '.', so it never collides with a function name.
+func methodName(typ, name string) string {
+	return typ + "." + name
+}
+
+// overloadName mangles the name of one of the functions sharing it with the
+// types of its arguments, leaving out the receiver of methods.
+func overloadName(p *PrototypeAST) string {
+	args := p.Args
+	if p.Receiver != "" {
+		args = args[1:]
+	}
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = arg.ArgType
+	}
+	return p.Name + "(" + strings.Join(types, ",") + ")"
+}
+
+// sourceName is the name of a function as written in its declaration, without
+// the receiver of methods or the argument types of overloads.
+func sourceName(p *PrototypeAST) string {
+	name := p.Name
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	if p.Receiver != "" {
+		name = name[len(p.Receiver)+1:]
+	}
+	return name
 }
 
 type FunctionAST struct {
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// builtins implement the extern functions the interpreter can call and the
+// intrinsics. Externs can be declared with any number types, so their
+// builtins cast the arguments they read.
+var builtins = map[string]func(in *Interpreter, args []interface{}) interface{}{
+	"printf":    builtinPrintf,
+	"putchar":   builtinPutchar,
+	"exit":      builtinExit,
+	"identical": builtinIdentical,
+	"len":       builtinLen,
+	"str":       builtinStr,
+}
+
+func builtinPrintf(in *Interpreter, args []interface{}) interface{} {
+	n, _ := fmt.Fprint(in.out, formatC(args[0].(string), args[1:]))
+	return int32(n)
+}
+
+func builtinPutchar(in *Interpreter, args []interface{}) interface{} {
+	c := castValue(args[0], LitChar).(byte)
+	in.out.Write([]byte{c})
+	return int32(c)
+}
+
+func builtinExit(in *Interpreter, args []interface{}) interface{} {
+	panic(exitProgram{int(castValue(args[0], LitInt).(int32))})
+}
+
+// builtinIdentical compares where the bytes of both strings are. Go shares
+// them between copies of a string value, like compiled code shares pointers.
+func builtinIdentical(in *Interpreter, args []interface{}) interface{} {
+	a, b := args[0].(string), args[1].(string)
+	headerA := (*reflect.StringHeader)(unsafe.Pointer(&a))
+	headerB := (*reflect.StringHeader)(unsafe.Pointer(&b))
+	return headerA.Data == headerB.Data && headerA.Len == headerB.Len
+}
+
+func builtinStr(in *Interpreter, args []interface{}) interface{} {
+	return string([]byte{castValue(args[0], LitChar).(byte)})
+}
+
+func builtinLen(in *Interpreter, args []interface{}) interface{} {
+	if str, ok := args[0].(string); ok {
+		return int32(len(str))
+	}
+	return int32(len(args[0].([]interface{})))
+}
+
+// formatC expands a C printf format. Go's fmt understands the same flags and
+// verbs for the types novum has, so only length modifiers and the verbs Go
+// spells differently need translating.
+func formatC(format string, args []interface{}) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			b.WriteByte(format[i])
+			continue
+		}
+
+		j := i + 1
+		for j < len(format) && strings.IndexByte("-+ #0123456789.", format[j]) >= 0 {
+			j++
+		}
+		spec := format[i:j]
+		for j < len(format) && strings.IndexByte("hlLqjzt", format[j]) >= 0 {
+			j++
+		}
+		if j == len(format) {
+			b.WriteString(format[i:])
+			break
+		}
+
+		verb := format[j]
+		i = j
+		switch verb {
+		case '%':
+			b.WriteByte('%')
+			continue
+		case 'i', 'u':
+			verb = 'd'
+		case 'd', 's', 'c', 'f', 'F', 'e', 'E', 'g', 'G', 'x', 'X', 'o':
+		default:
+			b.WriteString(spec + string(verb))
+			continue
+		}
+
+		if len(args) == 0 {
+			fmt.Fprintf(&b, spec+string(verb))
+			continue
+		}
+		fmt.Fprintf(&b, spec+string(verb), args[0])
+		args = args[1:]
+	}
+	return b.String()
+}
@@ -0,0 +1,1556 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// builtinOps lists the binary operators code generation implements for each
+// type, and whether the operator compares its operands.
+var builtinOps = map[string]map[string]bool{
+	LitInt: {
+		"+": false, "-": false, "*": false, "/": false, "%": false,
+		"&": false, "|": false, "^": false, "<<": false, ">>": false,
+		"<": true, "<=": true, ">": true, ">=": true, "==": true, "!=": true,
+	},
+	LitFloat: {
+		"+": false, "-": false, "*": false, "/": false, "%": false,
+		"<": true, "<=": true, ">": true, ">=": true, "==": true, "!=": true,
+	},
+	LitString: {
+		"+": false,
+		"<": true, "<=": true, ">": true, ">=": true, "==": true, "!=": true,
+	},
+	LitBool: {
+		"&&": false, "||": false, "&": false, "|": false, "^": false,
+		"==": true, "!=": true,
+	},
+	LitChar: {
+		"<": true, "<=": true, ">": true, ">=": true, "==": true, "!=": true,
+	},
+	LitAtom: {
+		"==": true, "!=": true,
+	},
+}
+
+// builtinUnaryOps lists the unary operators code generation implements for
+// each type.
+var builtinUnaryOps = map[string]map[string]bool{
+	LitInt:   {"-": true},
+	LitFloat: {"-": true},
+	LitBool:  {"!": true},
+}
+
+// The sized numeric types have the operators of int or float. Unsigned ones
+// can't be negated.
+func init() {
+	for typ, info := range numberTypes {
+		switch {
+		case info.float:
+			builtinOps[typ] = builtinOps[LitFloat]
+			builtinUnaryOps[typ] = builtinUnaryOps[LitFloat]
+		case info.signed:
+			builtinOps[typ] = builtinOps[LitInt]
+			builtinUnaryOps[typ] = builtinUnaryOps[LitInt]
+		default:
+			builtinOps[typ] = builtinOps[LitInt]
+		}
+	}
+}
+
+// binding is a variable in scope. what describes where it comes from for
+// error notes.
+type binding struct {
+	typ      string
+	mutable  bool
+	inferred bool // the type comes from the value
+	pos      Pos
+	what     string
+}
+
+// Checker resolves names and verifies types of the parsed declarations before
+// any IR is built. Every expression it visits gets its type annotated, so the
+// backends can rely on it instead of guessing from the generated values.
+type Checker struct {
+	functions map[string]*PrototypeAST // by mangled name
+	overloads map[string][]*PrototypeAST
+	externs   map[string]bool
+	structs   map[string]*StructAST
+	enums     map[string]*EnumAST
+	scopes    []map[string]*binding
+	current   *PrototypeAST
+	loops     []*LoopAST // enclosing the statement being checked
+	diags     []Diagnostic
+
+	// bodies holds the functions returning their expression body, whose
+	// return type is inferred. checked is false while a function is checked
+	// and true after.
+	bodies  map[string]*FunctionAST
+	checked map[*FunctionAST]bool
+
+	// literals holds the number literals of the statement being checked,
+	// negated the ones that are the operand of a '-'
+	literals []*NumberLiteralAST
+	negated  map[*NumberLiteralAST]bool
+}
+
+func checkModule(decls []AST) []Diagnostic {
+	c := Checker{
+		functions: map[string]*PrototypeAST{},
+		overloads: map[string][]*PrototypeAST{},
+		externs:   map[string]bool{},
+		structs:   map[string]*StructAST{},
+		enums:     map[string]*EnumAST{},
+		bodies:    map[string]*FunctionAST{},
+		checked:   map[*FunctionAST]bool{},
+		negated:   map[*NumberLiteralAST]bool{},
+	}
+
+	for i := range intrinsics {
+		c.functions[intrinsics[i].Name] = &intrinsics[i]
+	}
+
+	// Types come first, so that everything can use them
+	var structs []*StructAST
+	var enums []*EnumAST
+	for _, decl := range decls {
+		switch d := decl.(type) {
+		case *StructAST:
+			if c.declareType(d.Name, d.Pos, "Struct") {
+				c.structs[d.Name] = d
+			}
+			structs = append(structs, d)
+		case *EnumAST:
+			if c.declareType(d.Name, d.Pos, "Enum") {
+				c.enums[d.Name] = d
+			}
+			enums = append(enums, d)
+		}
+	}
+	for _, s := range structs {
+		c.checkStruct(s)
+	}
+	for _, e := range enums {
+		c.checkEnum(e)
+	}
+
+	for _, decl := range decls {
+		switch d := decl.(type) {
+		case *FunctionAST:
+			c.declare(&d.Proto, false)
+		case *PrototypeAST:
+			c.declare(d, true)
+		}
+	}
+	c.mangleOverloads()
+	for _, decl := range decls {
+		if d, ok := decl.(*FunctionAST); ok && d.Proto.ReturnType == "" && c.functions[d.Proto.Name] == &d.Proto {
+			c.bodies[d.Proto.Name] = d
+		}
+	}
+
+	for _, decl := range decls {
+		// Calls of functions with an inferred return type check them early
+		if function, ok := decl.(*FunctionAST); ok {
+			if _, done := c.checked[function]; !done {
+				c.checkFunction(function)
+			}
+		}
+	}
+
+	return c.diags
+}
+
+// errorAt reports an error spanning length characters from pos.
+func (c *Checker) errorAt(pos Pos, length int, code, err string, notes ...string) {
+	c.report(SeverityError, pos, length, code, err, notes)
+}
+
+// warningAt reports a warning spanning length characters from pos.
+func (c *Checker) warningAt(pos Pos, length int, code, warning string, notes ...string) {
+	c.report(SeverityWarning, pos, length, code, warning, notes)
+}
+
+func (c *Checker) report(severity Severity, pos Pos, length int, code, message string, notes []string) {
+	end := pos
+	end.col += length
+	c.diags = append(c.diags, Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Start:    pos,
+		End:      end,
+		Message:  message,
+		Notes:    notes,
+	})
+}
+
+func (c *Checker) isValueType(t string) bool {
+	if elem, _, ok := elemType(t); ok {
+		return c.isValueType(elem)
+	}
+
+	if _, ok := c.structs[t]; ok {
+		return true
+	}
+	if _, ok := c.enums[t]; ok {
+		return true
+	}
+	if _, ok := numberTypes[t]; ok {
+		return true
+	}
+
+	switch t {
+	case LitFloat, LitString, LitBool, LitInt, LitChar, LitAtom:
+		return true
+	}
+	return false
+}
+
+func (c *Checker) checkType(t string, pos Pos, allowVoid bool) bool {
+	if _, _, ok := elemType(t); ok {
+		base := t
+		for {
+			elem, _, ok := elemType(base)
+			if !ok {
+				break
+			}
+			base = elem
+		}
+
+		switch {
+		case c.isValueType(base):
+			return true
+		case base == LitVoid:
+			c.errorAt(pos, len(t), ErrUnknownType, "Arrays can't hold values of type void.")
+		default:
+			c.errorAt(pos, len(t), ErrUnknownType, fmt.Sprintf("Type %s doesn't exist.", base))
+		}
+		return false
+	}
+
+	switch {
+	case c.isValueType(t):
+		return true
+	case t == LitVoid:
+		if allowVoid {
+			return true
+		}
+		c.errorAt(pos, len(t), ErrUnknownType, "Type void can only be used as a return type.")
+		return false
+	default:
+		c.errorAt(pos, len(t), ErrUnknownType, fmt.Sprintf("Type %s doesn't exist.", t))
+		return false
+	}
+}
+
+// declareType tells whether the name of a struct or an enum is still free,
+// reporting an error when it isn't.
+func (c *Checker) declareType(name string, pos Pos, what string) bool {
+	var prev Pos
+	if s, ok := c.structs[name]; ok {
+		prev = s.Pos
+	} else if e, ok := c.enums[name]; ok {
+		prev = e.Pos
+	} else if c.isValueType(name) || name == LitVoid {
+		c.errorAt(pos, len(name), ErrRedefinition, fmt.Sprintf(`%s "%s" is already defined.`, what, name),
+			"it is a built-in type")
+		return false
+	} else {
+		return true
+	}
+
+	c.errorAt(pos, len(name), ErrRedefinition, fmt.Sprintf(`%s "%s" is already defined.`, what, name),
+		fmt.Sprintf("the previous definition is at %s:%d:%d", prev.file, prev.row, prev.col))
+	return false
+}
+
+func (c *Checker) checkStruct(s *StructAST) {
+	seen := map[string]Pos{}
+	for _, field := range s.Fields {
+		if prev, ok := seen[field.Name]; ok {
+			c.errorAt(field.Pos, len(field.Name), ErrRedefinition,
+				fmt.Sprintf(`Field "%s" is already defined.`, field.Name),
+				fmt.Sprintf("the previous definition is at %d:%d", prev.row, prev.col))
+		}
+		seen[field.Name] = field.Pos
+
+		if c.checkType(field.ArgType, field.TypePos, false) && c.contains(field.ArgType, s.Name, map[string]bool{}) {
+			c.errorAt(field.TypePos, len(field.ArgType), ErrRecursiveStruct,
+				fmt.Sprintf(`Struct "%s" can't contain itself.`, s.Name),
+				"arrays of it can be used instead, they are references")
+		}
+	}
+}
+
+func (c *Checker) checkEnum(e *EnumAST) {
+	seen := map[string]Pos{}
+	for _, variant := range e.Variants {
+		if prev, ok := seen[variant.Name]; ok {
+			c.errorAt(variant.Pos, len(variant.Name), ErrRedefinition,
+				fmt.Sprintf(`Variant "%s" is already defined.`, variant.Name),
+				fmt.Sprintf("the previous definition is at %d:%d", prev.row, prev.col))
+		}
+		seen[variant.Name] = variant.Pos
+
+		for i, typ := range variant.Payload {
+			if c.checkType(typ, variant.PayloadPos[i], false) && c.contains(typ, e.Name, map[string]bool{}) {
+				c.errorAt(variant.PayloadPos[i], len(typ), ErrRecursiveStruct,
+					fmt.Sprintf(`Enum "%s" can't contain itself.`, e.Name),
+					"arrays of it can be used instead, they are references")
+			}
+		}
+	}
+}
+
+// contains tells whether a value of type t holds a value of the struct or
+// enum name directly, rather than through an array. seen holds the types
+// already looked into.
+func (c *Checker) contains(t, name string, seen map[string]bool) bool {
+	if seen[t] {
+		return false
+	}
+
+	var members []string
+	if s, ok := c.structs[t]; ok {
+		for _, field := range s.Fields {
+			members = append(members, field.ArgType)
+		}
+	} else if e, ok := c.enums[t]; ok {
+		for _, variant := range e.Variants {
+			members = append(members, variant.Payload...)
+		}
+	} else {
+		return false
+	}
+	if t == name {
+		return true
+	}
+
+	seen[t] = true
+	for _, member := range members {
+		if c.contains(member, name, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// variant returns the tag and the payload of a variant of an enum.
+func (c *Checker) variant(enum, name string) (int, []string, bool) {
+	e, ok := c.enums[enum]
+	if !ok {
+		return 0, nil, false
+	}
+	for i, variant := range e.Variants {
+		if variant.Name == name {
+			return i, variant.Payload, true
+		}
+	}
+	return 0, nil, false
+}
+
+// field returns the index and the type of a struct field.
+func (c *Checker) field(structName, name string) (int, string, bool) {
+	for i, field := range c.structs[structName].Fields {
+		if field.Name == name {
+			return i, field.ArgType, true
+		}
+	}
+	return 0, "", false
+}
+
+// declare adds a function to the overloads of its name. Functions can share
+// a name when their arguments differ, except for built-in and extern ones and
+// main. Operators can't be defined for types that have them built in.
+func (c *Checker) declare(proto *PrototypeAST, extern bool) {
+	for _, arg := range proto.Args {
+		c.checkType(arg.ArgType, arg.TypePos, false)
+	}
+	if proto.ReturnType != "" {
+		c.checkType(proto.ReturnType, proto.ReturnTypePos, true)
+	}
+
+	if proto.IsOperator && builtinOperator(proto) {
+		op, types := operatorSignature(proto)
+		c.errorAt(proto.Pos, len(op), ErrRedefinition,
+			fmt.Sprintf("Operator '%s' is already defined for (%s).", op, types), "it is a built-in operator")
+		return
+	}
+
+	prevs := c.overloads[proto.Name]
+	if builtin, ok := c.functions[proto.Name]; ok {
+		prevs = []*PrototypeAST{builtin}
+	}
+	overloadable := !extern && !c.externs[proto.Name] && proto.Name != "main"
+	for _, prev := range prevs {
+		if overloadable && prev.row != 0 && !sameArgs(prev, proto) {
+			continue
+		}
+
+		note := fmt.Sprintf("the previous definition is at %s:%d:%d", prev.file, prev.row, prev.col)
+		if prev.row == 0 {
+			note = "it is a built-in function"
+		}
+		if proto.Receiver != "" {
+			name := sourceName(proto)
+			c.errorAt(proto.Pos, len(name), ErrRedefinition,
+				fmt.Sprintf(`Method "%s" of %s is already defined.`, name, proto.Receiver), note)
+			return
+		}
+		if proto.IsOperator {
+			op, types := operatorSignature(proto)
+			c.errorAt(proto.Pos, len(op), ErrRedefinition,
+				fmt.Sprintf("Operator '%s' is already defined for (%s).", op, types), note)
+			return
+		}
+		c.errorAt(proto.Pos, len(proto.Name), ErrRedefinition,
+			fmt.Sprintf(`Function "%s" is already defined.`, proto.Name), note)
+		return
+	}
+
+	c.overloads[proto.Name] = append(c.overloads[proto.Name], proto)
+	if extern {
+		c.externs[proto.Name] = true
+	}
+}
+
+// operatorSignature returns the symbol of a user defined operator and the
+// types of its operands.
+func operatorSignature(proto *PrototypeAST) (string, string) {
+	types := make([]string, len(proto.Args))
+	for i, arg := range proto.Args {
+		types[i] = arg.ArgType
+	}
+	op := strings.TrimPrefix(strings.TrimPrefix(proto.Name, "binary_"), "unary_")
+	return op, strings.Join(types, ", ")
+}
+
+// builtinOperator tells whether code generation implements the operator
+// defined by proto for its operand types already.
+func builtinOperator(proto *PrototypeAST) bool {
+	op, _ := operatorSignature(proto)
+	switch {
+	case proto.IsBinaryOp && len(proto.Args) == 2:
+		_, ok := builtinOps[proto.Args[0].ArgType][op]
+		return ok && proto.Args[0].ArgType == proto.Args[1].ArgType
+	case !proto.IsBinaryOp && len(proto.Args) == 1:
+		return builtinUnaryOps[proto.Args[0].ArgType][op]
+	}
+	return false
+}
+
+func sameArgs(a, b *PrototypeAST) bool {
+	if len(a.Args) != len(b.Args) {
+		return false
+	}
+	for i := range a.Args {
+		if a.Args[i].ArgType != b.Args[i].ArgType {
+			return false
+		}
+	}
+	return true
+}
+
+// mangleOverloads registers the declared functions. A function alone with its
+// name keeps it, the ones sharing it are renamed by overloadName, so that each
+// has a symbol of its own.
+func (c *Checker) mangleOverloads() {
+	for name, protos := range c.overloads {
+		if len(protos) == 1 {
+			c.functions[name] = protos[0]
+			continue
+		}
+		for _, proto := range protos {
+			proto.Name = overloadName(proto)
+			c.functions[proto.Name] = proto
+		}
+	}
+}
+
+func (c *Checker) pushScope() {
+	c.scopes = append(c.scopes, map[string]*binding{})
+}
+
+func (c *Checker) popScope() {
+	c.scopes = c.scopes[:len(c.scopes)-1]
+}
+
+func (c *Checker) define(name string, b *binding) {
+	c.scopes[len(c.scopes)-1][name] = b
+}
+
+func (c *Checker) lookup(name string) (*binding, bool) {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if b, ok := c.scopes[i][name]; ok {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+func (c *Checker) checkFunction(f *FunctionAST) {
+	c.checked[f] = false
+	defer func() { c.checked[f] = true }()
+
+	// A call can check the function it calls in the middle of another one
+	current, loops, scopes, literals := c.current, c.loops, c.scopes, c.literals
+	defer func() { c.current, c.loops, c.scopes, c.literals = current, loops, scopes, literals }()
+	c.current = &f.Proto
+	c.loops = nil
+	c.scopes = nil
+	c.literals = nil
+	c.pushScope()
+
+	for _, arg := range f.Proto.Args {
+		if _, ok := c.scopes[0][arg.Name]; ok {
+			c.errorAt(arg.Pos, len(arg.Name), ErrRedefinition,
+				fmt.Sprintf(`Argument "%s" is already defined.`, arg.Name))
+		}
+		// Uses of arguments with an unknown type were already reported
+		typ := arg.ArgType
+		if !c.isValueType(typ) {
+			typ = ""
+		}
+		c.define(arg.Name, &binding{typ: typ, pos: arg.Pos, what: "an argument"})
+	}
+
+	if f.Proto.ReturnType == "" {
+		c.inferReturn(f)
+		return
+	}
+	c.checkBlock(&f.Body)
+	c.checkFlow(f)
+}
+
+// inferReturn checks the expression body of the function and makes its type
+// the return type. A void expression is evaluated before returning nothing.
+func (c *Checker) inferReturn(f *FunctionAST) {
+	ret := f.Body.Elements[0].(*ReturnAST)
+	typ := c.checkExpr(ret.Body)
+	c.checkLiterals()
+	if typ == LitVoid {
+		f.Body.Elements = []AST{ret.Body, &ReturnAST{ret.Pos, astReturn, nil, true}}
+	}
+	f.Proto.ReturnType = typ
+}
+
+// returnType returns the return type of a function called at pos. A type
+// inferred from the body of the function needs the body checked first.
+func (c *Checker) returnType(proto *PrototypeAST, pos Pos, length int) string {
+	f, ok := c.bodies[proto.Name]
+	if !ok {
+		return proto.ReturnType
+	}
+
+	done, started := c.checked[f]
+	switch {
+	case !started:
+		c.checkFunction(f)
+	case !done:
+		c.errorAt(pos, length, ErrReturn,
+			fmt.Sprintf(`Can't infer the return type of "%s", it depends on itself.`, sourceName(proto)),
+			"write the return type after the arguments")
+	}
+	return proto.ReturnType
+}
+
+// inferredNote explains where the type of a variable or a call comes from
+// when it was inferred rather than written.
+func (c *Checker) inferredNote(expr AST) []string {
+	switch e := expr.(type) {
+	case *VariableAST:
+		if b, ok := c.lookup(e.Name); ok && b.inferred {
+			return []string{fmt.Sprintf(`the type of "%s" is inferred from its value at %d:%d`, e.Name, b.pos.row, b.pos.col)}
+		}
+	case *CallAST:
+		if f, ok := c.bodies[e.Callee]; ok {
+			return []string{fmt.Sprintf(`the return type of "%s" is inferred from its body at %d:%d`, e.Callee, f.Body.row, f.Body.col)}
+		}
+	}
+	return nil
+}
+
+func (c *Checker) checkBlock(b *BlockAST) {
+	c.pushScope()
+	defer c.popScope()
+
+	for _, stmt := range b.Elements {
+		c.checkStmt(stmt)
+	}
+}
+
+func (c *Checker) checkStmt(stmt AST) {
+	defer c.checkLiterals()
+
+	switch s := stmt.(type) {
+	case *IfElseAST:
+		c.checkCondition(s.Condition, "if")
+		c.checkBlock(&s.TrueBody)
+		for i := range s.ElseIfBody {
+			c.checkCondition(s.ElseIfBody[i].Condition, "else if")
+			c.checkBlock(&s.ElseIfBody[i].Body)
+		}
+		c.checkBlock(&s.ElseBody)
+	case *LoopAST:
+		c.checkLoop(s)
+	case *ReturnAST:
+		c.checkReturn(s)
+	case *VarDeclAST:
+		c.checkVarDecl(s)
+	case *AssignAST:
+		c.checkAssign(s)
+	case *BranchAST:
+		c.checkBranch(s)
+	default:
+		c.checkExpr(stmt)
+	}
+}
+
+func (c *Checker) checkCondition(cond AST, what string) {
+	typ := c.checkExpr(cond)
+	if typ != "" && typ != LitBool {
+		c.errorAt(cond.Position(), 1, ErrTypeMismatch,
+			fmt.Sprintf("Condition of '%s' must be bool, found %s.", what, typ))
+	}
+}
+
+func (c *Checker) checkLoop(l *LoopAST) {
+	if l.Label != "" {
+		for _, outer := range c.loops {
+			if outer.Label == l.Label {
+				c.errorAt(l.Pos, 3, ErrLoopControl,
+					fmt.Sprintf(`Label "%s" is already used by an enclosing loop.`, l.Label),
+					fmt.Sprintf("the other loop is at %d:%d", outer.row, outer.col))
+			}
+		}
+	}
+	c.loops = append(c.loops, l)
+	defer func() { c.loops = c.loops[:len(c.loops)-1] }()
+
+	if !l.forIn {
+		c.checkCondition(l.Condition, "for")
+		c.checkBlock(&l.Body)
+		return
+	}
+
+	elem := LitChar
+	if r, ok := l.Condition.(*RangeAST); ok {
+		c.checkRange(r)
+		elem = LitInt
+	} else if typ := c.checkExpr(l.Condition); typ != "" && typ != LitString {
+		arrayElem, _, ok := elemType(typ)
+		if !ok {
+			c.errorAt(l.Condition.Position(), 1, ErrTypeMismatch,
+				fmt.Sprintf("Can't iterate over a value of type %s.", typ))
+		}
+		elem = arrayElem
+	}
+
+	c.pushScope()
+	defer c.popScope()
+	if l.IndexVar != "" {
+		c.define(l.IndexVar, &binding{typ: LitInt, pos: l.Pos, what: "a loop variable"})
+	}
+	c.define(l.ElementVar, &binding{typ: elem, pos: l.Pos, what: "a loop variable"})
+	c.checkBlock(&l.Body)
+}
+
+func (c *Checker) checkRange(r *RangeAST) {
+	bounds := []AST{r.Start, r.End}
+	if r.Step != nil {
+		bounds = append(bounds, r.Step)
+	}
+	for _, bound := range bounds {
+		if typ := c.checkExpr(bound); typ != "" && typ != LitInt {
+			c.errorAt(bound.Position(), 1, ErrTypeMismatch,
+				fmt.Sprintf("Ranges are made of int values, found %s.", typ))
+		}
+	}
+
+	if step, ok := r.Step.(*NumberLiteralAST); ok && step.Value == 0 {
+		c.errorAt(step.Pos, 1, ErrInvalidRange, "Step of a range can't be 0.")
+	}
+}
+
+func (c *Checker) checkBranch(b *BranchAST) {
+	keyword := "break"
+	if b.Kind() == astContinue {
+		keyword = "continue"
+	}
+
+	if len(c.loops) == 0 {
+		c.errorAt(b.Pos, len(keyword), ErrLoopControl,
+			fmt.Sprintf("'%s' can only be used inside a loop.", keyword))
+		return
+	}
+	if b.Label == "" {
+		return
+	}
+	for _, l := range c.loops {
+		if l.Label == b.Label {
+			return
+		}
+	}
+	c.errorAt(b.Pos, len(keyword), ErrLoopControl,
+		fmt.Sprintf(`There is no enclosing loop labelled "%s".`, b.Label))
+}
+
+func (c *Checker) checkVarDecl(v *VarDeclAST) {
+	inferred := false
+	typ := c.checkExpr(v.Value)
+	if typ == LitVoid {
+		c.errorAt(v.Value.Position(), 1, ErrTypeMismatch,
+			fmt.Sprintf(`Variable "%s" can't hold a value of type void.`, v.Name))
+		typ = ""
+	}
+
+	if v.VarType != "" {
+		if !c.checkType(v.VarType, v.TypePos, false) {
+			typ = ""
+		} else {
+			if typ != "" {
+				typ = c.convert(&v.Value, v.VarType)
+			}
+			if typ != "" && !converts(v.VarType, typ) {
+				c.errorAt(v.Value.Position(), 1, ErrTypeMismatch,
+					fmt.Sprintf(`Variable "%s" is %s, found %s.`, v.Name, v.VarType, typ), c.inferredNote(v.Value)...)
+			}
+			typ = v.VarType
+		}
+	} else {
+		inferred = true
+	}
+	v.VarType = typ
+
+	if prev, ok := c.scopes[len(c.scopes)-1][v.Name]; ok {
+		c.errorAt(v.Pos, len(v.Name), ErrRedefinition,
+			fmt.Sprintf(`Variable "%s" is already defined in this block.`, v.Name),
+			fmt.Sprintf("the previous definition is at %d:%d", prev.pos.row, prev.pos.col))
+	}
+
+	what := "let"
+	if v.Mutable {
+		what = "var"
+	}
+	c.define(v.Name, &binding{typ: typ, mutable: v.Mutable, inferred: inferred, pos: v.Pos, what: what})
+}
+
+func (c *Checker) checkAssign(a *AssignAST) {
+	typ := c.checkExpr(a.Target)
+	valType := c.checkExpr(a.Value)
+
+	// Elements and fields can be assigned when they are in a mutable variable
+	root := a.Target
+loop:
+	for {
+		switch target := root.(type) {
+		case *IndexAST:
+			root = target.Array
+		case *FieldAST:
+			root = target.Struct
+		default:
+			break loop
+		}
+	}
+	variable, ok := root.(*VariableAST)
+	if !ok {
+		c.errorAt(a.Pos, 1, ErrImmutableAssign, "Only variables and the elements and fields of values in variables can be assigned.")
+		return
+	}
+	if typ == "" || variable.VarType == "" {
+		return
+	}
+
+	if !variable.Mutable {
+		b, _ := c.lookup(variable.Name)
+		note := fmt.Sprintf("it is declared with 'let' at %d:%d, use 'var' to make it mutable", b.pos.row, b.pos.col)
+		if b.what != "let" {
+			note = fmt.Sprintf("it is %s declared at %d:%d", b.what, b.pos.row, b.pos.col)
+		}
+		c.errorAt(variable.Pos, len(variable.Name), ErrImmutableAssign,
+			fmt.Sprintf(`Can't assign to immutable variable "%s".`, variable.Name), note)
+	}
+
+	if valType != "" {
+		valType = c.convert(&a.Value, typ)
+	}
+	if valType != "" && !converts(typ, valType) {
+		what := fmt.Sprintf(`Variable "%s"`, variable.Name)
+		switch target := a.Target.(type) {
+		case *IndexAST:
+			what = fmt.Sprintf(`Element of "%s"`, variable.Name)
+		case *FieldAST:
+			what = fmt.Sprintf(`Field "%s"`, target.Name)
+		}
+		notes := c.inferredNote(a.Target)
+		if notes == nil {
+			notes = c.inferredNote(a.Value)
+		}
+		c.errorAt(a.Value.Position(), 1, ErrTypeMismatch,
+			fmt.Sprintf(`%s is %s, found %s.`, what, typ, valType), notes...)
+	}
+}
+
+// funcTitle names a function as written to start a message. Methods are
+// named with the type they are defined on.
+func funcTitle(p *PrototypeAST) string {
+	if p.Receiver != "" {
+		return fmt.Sprintf(`Method "%s" of %s`, sourceName(p), p.Receiver)
+	}
+	return fmt.Sprintf(`Function "%s"`, sourceName(p))
+}
+
+func (c *Checker) checkReturn(r *ReturnAST) {
+	want := c.current.ReturnType
+	if r.Body == nil {
+		if want != LitVoid {
+			c.errorAt(r.Pos, len("return"), ErrReturn,
+				fmt.Sprintf(`%s must return a value of type %s.`, funcTitle(c.current), want))
+		}
+		return
+	}
+
+	typ := c.checkExpr(r.Body)
+	if want == LitVoid {
+		c.errorAt(r.Body.Position(), 1, ErrReturn,
+			fmt.Sprintf(`%s doesn't return a value.`, funcTitle(c.current)))
+		return
+	}
+
+	if typ != "" {
+		typ = c.convert(&r.Body, want)
+	}
+	if typ != "" && !converts(want, typ) {
+		c.errorAt(r.Body.Position(), 1, ErrTypeMismatch,
+			fmt.Sprintf(`%s returns %s, found %s.`, funcTitle(c.current), want, typ), c.inferredNote(r.Body)...)
+	}
+}
+
+// checkExpr annotates the expression and returns its type. An empty type
+// means an error was already reported, so callers don't report it again.
+func (c *Checker) checkExpr(expr AST) string {
+	switch e := expr.(type) {
+	case *NumberLiteralAST:
+		if e.Kind() == astNumberFloat {
+			e.setType(LitFloat)
+		} else {
+			e.setType(LitInt)
+		}
+		c.literals = append(c.literals, e)
+		return e.Type()
+	case *StringAST:
+		e.setType(LitString)
+		return e.Type()
+	case *CharAST:
+		e.setType(LitChar)
+		return e.Type()
+	case *AtomAST:
+		e.setType(LitAtom)
+		return e.Type()
+	case *BoolAST:
+		e.setType(LitBool)
+		return e.Type()
+	case *VariableAST:
+		b, ok := c.lookup(e.Name)
+		if !ok {
+			c.errorAt(e.Pos, len(e.Name), ErrUnknownVariable, fmt.Sprintf(`Variable "%s" does not exist!`, e.Name))
+			return ""
+		}
+		e.setType(b.typ)
+		e.Mutable = b.mutable
+		return b.typ
+	case *CallAST:
+		return c.checkCall(e)
+	case *BinaryAST:
+		return c.checkBinary(e)
+	case *UnaryAST:
+		return c.checkUnary(e)
+	case *ArrayAST:
+		return c.checkArray(e)
+	case *IndexAST:
+		return c.checkIndex(e)
+	case *StructLitAST:
+		return c.checkStructLit(e)
+	case *FieldAST:
+		return c.checkField(e)
+	case *VariantAST:
+		return c.checkVariant(e)
+	case *MatchAST:
+		return c.checkMatch(e)
+	case *CastAST:
+		return c.checkCast(e)
+	default:
+		c.errorAt(expr.Position(), 1, ErrUnexpectedToken, "Expected an expression.")
+		return ""
+	}
+}
+
+func (c *Checker) checkCall(call *CallAST) string {
+	for _, arg := range call.args {
+		c.checkExpr(arg)
+	}
+
+	name := call.Callee
+	if call.Method {
+		receiver := typeOf(call.args[0])
+		if receiver == "" {
+			return ""
+		}
+		callee, ok := c.method(receiver, name)
+		if !ok {
+			c.errorAt(call.Pos, len(name), ErrUnknownFunction, fmt.Sprintf(`Type %s has no method "%s".`, receiver, name))
+			return ""
+		}
+		call.Callee = callee
+	}
+
+	proto, ok := c.functions[call.Callee]
+	if overloads := c.overloads[call.Callee]; len(overloads) > 1 {
+		if proto, ok = c.resolve(call, name, overloads); !ok {
+			return ""
+		}
+		call.Callee = proto.Name
+	}
+	if !ok {
+		c.errorAt(call.Pos, len(name), ErrUnknownFunction, fmt.Sprintf(`Function "%s" does not exist.`, name))
+		return ""
+	}
+
+	if len(call.args) != len(proto.Args) {
+		// The receiver of a method call isn't one of the written arguments.
+		skip := 0
+		if call.Method {
+			skip = 1
+		}
+		c.errorAt(call.Pos, len(name), ErrArgCount,
+			fmt.Sprintf(`%s takes %d arguments, got %d.`, funcTitle(proto), len(proto.Args)-skip, len(call.args)-skip),
+			fmt.Sprintf("it is defined at %s:%d:%d", proto.file, proto.row, proto.col))
+	} else if proto.row == 0 && proto.Name == "len" {
+		if typ := typeOf(call.args[0]); typ != "" && typ != LitString {
+			if _, _, ok := elemType(typ); !ok {
+				c.errorAt(call.args[0].Position(), 1, ErrTypeMismatch,
+					fmt.Sprintf("Can't take the length of a value of type %s.", typ))
+			}
+		}
+	} else {
+		for i, arg := range call.args {
+			typ := typeOf(arg)
+			want := proto.Args[i].ArgType
+			if typ != "" {
+				typ = c.convert(&call.args[i], want)
+			}
+			if typ != "" && c.isValueType(want) && !converts(want, typ) {
+				c.errorAt(arg.Position(), 1, ErrTypeMismatch,
+					fmt.Sprintf(`Argument "%s" of "%s" must be %s, found %s.`, proto.Args[i].Name, sourceName(proto), want, typ),
+					c.inferredNote(arg)...)
+			}
+		}
+	}
+
+	typ := c.returnType(proto, call.Pos, len(name))
+	call.setType(typ)
+	return typ
+}
+
+// method returns the mangled name of the method called name defined on typ.
+// Arrays of a fixed length also have the methods of arrays of any length.
+func (c *Checker) method(typ, name string) (string, bool) {
+	if _, ok := c.overloads[methodName(typ, name)]; ok {
+		return methodName(typ, name), true
+	}
+	if elem, n, ok := elemType(typ); ok && n >= 0 {
+		return c.method(sliceType(elem), name)
+	}
+	return "", false
+}
+
+// resolve picks the overload a call means: the one taking exactly the types
+// of the arguments, or else the only one they convert to.
+func (c *Checker) resolve(call *CallAST, name string, overloads []*PrototypeAST) (*PrototypeAST, bool) {
+	// The receiver of a method call is the same for every overload
+	skip := 0
+	if call.Method {
+		skip = 1
+	}
+	var types []string
+	for _, arg := range call.args[skip:] {
+		typ := typeOf(arg)
+		if typ == "" {
+			return nil, false
+		}
+		types = append(types, typ)
+	}
+
+	var exact, viable []*PrototypeAST
+	for _, proto := range overloads {
+		if len(proto.Args) != len(call.args) {
+			continue
+		}
+		same, convertible := true, true
+		for i, arg := range call.args {
+			typ, want := typeOf(arg), proto.Args[i].ArgType
+			same = same && typ == want
+			convertible = convertible && (converts(want, typ) || adapts(arg, want))
+		}
+		if same {
+			exact = append(exact, proto)
+		} else if convertible {
+			viable = append(viable, proto)
+		}
+	}
+	if len(exact) == 1 {
+		return exact[0], true
+	}
+	if len(viable) == 1 {
+		return viable[0], true
+	}
+
+	var notes []string
+	candidates := overloads
+	if len(viable) != 0 {
+		candidates = viable
+	}
+	for _, proto := range candidates {
+		notes = append(notes, fmt.Sprintf("%s is defined at %s:%d:%d", proto.Name, proto.file, proto.row, proto.col))
+	}
+	if len(viable) != 0 {
+		c.errorAt(call.Pos, len(name), ErrOverload,
+			fmt.Sprintf(`Call of "%s" with (%s) is ambiguous.`, name, strings.Join(types, ", ")), notes...)
+	} else {
+		c.errorAt(call.Pos, len(name), ErrOverload,
+			fmt.Sprintf(`No overload of "%s" takes (%s).`, name, strings.Join(types, ", ")), notes...)
+	}
+	return nil, false
+}
+
+func (c *Checker) checkArray(a *ArrayAST) string {
+	if len(a.Elements) == 0 {
+		c.errorAt(a.Pos, 1, ErrTypeMismatch, "Can't infer the element type of an empty array.")
+		return ""
+	}
+
+	// Literals take the type of the other elements
+	elem := ""
+	for i, e := range a.Elements {
+		typ := c.checkExpr(e)
+		switch {
+		case typ == "":
+		case typ == LitVoid:
+			c.errorAt(e.Position(), 1, ErrTypeMismatch, "Arrays can't hold values of type void.")
+			return ""
+		case elem == "":
+			elem = typ
+		case commonType(elem, typ) != "":
+			elem = commonType(elem, typ)
+		case adapts(e, elem):
+			retype(e, elem)
+		case adaptsAll(a.Elements[:i], typ):
+			retypeAll(a.Elements[:i], typ)
+			elem = typ
+		default:
+			c.errorAt(e.Position(), 1, ErrTypeMismatch,
+				fmt.Sprintf("Elements of the array are %s, found %s.", elem, typ))
+		}
+	}
+	if elem == "" {
+		return ""
+	}
+
+	a.setType(arrayType(elem, len(a.Elements)))
+	return a.Type()
+}
+
+func (c *Checker) checkStructLit(l *StructLitAST) string {
+	s, ok := c.structs[l.Name]
+	if !ok {
+		for _, field := range l.Fields {
+			c.checkExpr(field.Value)
+		}
+		c.errorAt(l.Pos, len(l.Name), ErrUnknownType, fmt.Sprintf("Struct %s doesn't exist.", l.Name))
+		return ""
+	}
+
+	given := map[string]bool{}
+	for i := range l.Fields {
+		field := &l.Fields[i]
+		typ := c.checkExpr(field.Value)
+
+		index, want, ok := c.field(l.Name, field.Name)
+		switch {
+		case !ok:
+			c.errorAt(field.Pos, len(field.Name), ErrStructField,
+				fmt.Sprintf(`Struct %s has no field "%s".`, l.Name, field.Name))
+			continue
+		case given[field.Name]:
+			c.errorAt(field.Pos, len(field.Name), ErrStructField,
+				fmt.Sprintf(`Field "%s" is given twice.`, field.Name))
+		case typ == "":
+		case !converts(want, c.convert(&field.Value, want)):
+			c.errorAt(field.Value.Position(), 1, ErrTypeMismatch,
+				fmt.Sprintf(`Field "%s" of %s is %s, found %s.`, field.Name, l.Name, want, typ), c.inferredNote(field.Value)...)
+		}
+		field.Index = index
+		given[field.Name] = true
+	}
+
+	var missing []string
+	for _, field := range s.Fields {
+		if !given[field.Name] {
+			missing = append(missing, `"`+field.Name+`"`)
+		}
+	}
+	if len(missing) != 0 {
+		c.errorAt(l.Pos, len(l.Name), ErrStructField,
+			fmt.Sprintf("Missing fields %s of %s.", strings.Join(missing, ", "), l.Name))
+	}
+
+	l.setType(l.Name)
+	return l.Type()
+}
+
+func (c *Checker) checkField(f *FieldAST) string {
+	typ := c.checkExpr(f.Struct)
+	if typ == "" {
+		return ""
+	}
+
+	if _, ok := c.structs[typ]; !ok {
+		c.errorAt(f.Pos, len(f.Name), ErrStructField,
+			fmt.Sprintf(`Type %s has no field "%s".`, typ, f.Name))
+		return ""
+	}
+	index, fieldType, ok := c.field(typ, f.Name)
+	if !ok {
+		c.errorAt(f.Pos, len(f.Name), ErrStructField,
+			fmt.Sprintf(`Struct %s has no field "%s".`, typ, f.Name))
+		return ""
+	}
+
+	f.Index = index
+	f.setType(fieldType)
+	return fieldType
+}
+
+func (c *Checker) checkVariant(v *VariantAST) string {
+	for _, arg := range v.Args {
+		c.checkExpr(arg)
+	}
+
+	tag, payload, ok := c.variant(v.Enum, v.Variant)
+	if !ok {
+		c.errorAt(v.Pos, len(v.Enum)+1+len(v.Variant), ErrEnumVariant,
+			fmt.Sprintf(`Enum %s has no variant "%s".`, v.Enum, v.Variant))
+		return ""
+	}
+
+	name := v.Enum + "." + v.Variant
+	if len(v.Args) != len(payload) {
+		c.errorAt(v.Pos, len(name), ErrArgCount,
+			fmt.Sprintf(`Variant "%s" holds %d values, got %d.`, name, len(payload), len(v.Args)))
+	} else {
+		for i, arg := range v.Args {
+			if typ := typeOf(arg); typ != "" && !converts(payload[i], c.convert(&v.Args[i], payload[i])) {
+				c.errorAt(arg.Position(), 1, ErrTypeMismatch,
+					fmt.Sprintf(`Value %d of "%s" must be %s, found %s.`, i+1, name, payload[i], typ), c.inferredNote(arg)...)
+			}
+		}
+	}
+
+	v.Tag = tag
+	v.setType(v.Enum)
+	return v.Enum
+}
+
+// checkMatch checks the patterns against the type of the value and that
+// together they match every value. Only a match without block arms has the
+// common type of its arms, the others are statements.
+func (c *Checker) checkMatch(m *MatchAST) string {
+	typ := c.checkExpr(m.Value)
+	_, isEnum := c.enums[typ]
+	info, isNumber := numberTypes[typ]
+	if typ != "" && !isEnum && (!isNumber || info.float) && typ != LitString && typ != LitAtom {
+		c.errorAt(m.Value.Position(), 1, ErrTypeMismatch, fmt.Sprintf("Can't match a value of type %s.", typ))
+		typ = ""
+	}
+
+	statement := false
+	for _, arm := range m.Arms {
+		if arm.Block != nil {
+			statement = true
+		}
+	}
+
+	// Literal arms take the type of the other arms
+	result := ""
+	broken := false
+	matched := map[string]Pos{}
+	var values []AST
+	var wildcard *MatchArm
+	for i := range m.Arms {
+		arm := &m.Arms[i]
+		if wildcard != nil {
+			c.errorAt(arm.Pos, 1, ErrMatch, "Arm can never run.",
+				fmt.Sprintf("the '_' arm at %d:%d matches every value", wildcard.row, wildcard.col))
+		} else if arm.wildcard() {
+			wildcard = arm
+		}
+
+		c.pushScope()
+		c.checkPattern(arm, typ, matched)
+		if arm.Block != nil {
+			c.checkBlock(arm.Block)
+		} else {
+			armType := c.checkExpr(arm.Value)
+			switch {
+			case statement:
+			case armType == "":
+				broken = true
+			case result == "":
+				result = armType
+			case commonType(result, armType) != "":
+				result = commonType(result, armType)
+			case adapts(arm.Value, result):
+				retype(arm.Value, result)
+			case adaptsAll(values, armType):
+				retypeAll(values, armType)
+				result = armType
+			default:
+				c.errorAt(arm.Value.Position(), 1, ErrTypeMismatch,
+					fmt.Sprintf("Arms of the match are %s, found %s.", result, armType))
+				broken = true
+			}
+			values = append(values, arm.Value)
+		}
+		c.popScope()
+	}
+
+	if wildcard == nil && typ != "" {
+		var missing []string
+		if isEnum {
+			for _, variant := range c.enums[typ].Variants {
+				if _, ok := matched[variant.Name]; !ok {
+					missing = append(missing, typ+"."+variant.Name)
+				}
+			}
+		} else {
+			missing = append(missing, "'_'")
+		}
+		if len(missing) != 0 {
+			c.errorAt(m.Pos, len("match"), ErrMatch,
+				fmt.Sprintf("Match doesn't handle every value, it needs %s.", strings.Join(missing, ", ")))
+		}
+	}
+
+	if broken {
+		return ""
+	}
+	if result == "" {
+		result = LitVoid
+	}
+	m.setType(result)
+	return result
+}
+
+// literalPattern spells the literal of a pattern like the source does.
+func literalPattern(literal AST) string {
+	switch literal := literal.(type) {
+	case *StringAST:
+		return strconv.Quote(literal.Value)
+	case *AtomAST:
+		return ":" + literal.Value
+	default:
+		return numberText(literal.(*NumberLiteralAST).Value)
+	}
+}
+
+// checkPattern checks the pattern of a match arm and defines the names it
+// binds. matched holds the variants and values of the arms before it.
+func (c *Checker) checkPattern(arm *MatchArm, typ string, matched map[string]Pos) {
+	var payload []string
+	switch {
+	case arm.wildcard():
+		return
+	case arm.Variant != "":
+		name := arm.Enum + "." + arm.Variant
+		tag, types, ok := c.variant(arm.Enum, arm.Variant)
+		switch {
+		case typ == "":
+		case arm.Enum != typ:
+			c.errorAt(arm.Pos, len(arm.Enum), ErrTypeMismatch,
+				fmt.Sprintf("Pattern is a variant of %s, but the value is %s.", arm.Enum, typ))
+		case !ok:
+			c.errorAt(arm.Pos, len(name), ErrEnumVariant,
+				fmt.Sprintf(`Enum %s has no variant "%s".`, arm.Enum, arm.Variant))
+		case len(arm.Bindings) != len(types):
+			c.errorAt(arm.Pos, len(name), ErrArgCount,
+				fmt.Sprintf(`Variant "%s" holds %d values, got %d.`, name, len(types), len(arm.Bindings)))
+		default:
+			payload = types
+		}
+		if prev, ok := matched[arm.Variant]; ok && payload != nil {
+			c.errorAt(arm.Pos, len(name), ErrMatch, fmt.Sprintf("Variant %s is already matched.", name),
+				fmt.Sprintf("the other arm is at %d:%d", prev.row, prev.col))
+		}
+		matched[arm.Variant] = arm.Pos
+		arm.Tag = tag
+	default:
+		litType := c.checkExpr(arm.Literal)
+		if typ != "" && litType != "" {
+			litType = c.convert(&arm.Literal, typ)
+		}
+		if typ != "" && litType != "" && litType != typ {
+			c.errorAt(arm.Pos, 1, ErrTypeMismatch, fmt.Sprintf("Pattern is %s, but the value is %s.", litType, typ))
+			return
+		}
+		value := literalPattern(arm.Literal)
+		if prev, ok := matched[value]; ok {
+			c.errorAt(arm.Pos, 1, ErrMatch, fmt.Sprintf("Value %s is already matched.", value),
+				fmt.Sprintf("the other arm is at %d:%d", prev.row, prev.col))
+		}
+		matched[value] = arm.Pos
+		return
+	}
+
+	for i, b := range arm.Bindings {
+		if b.Name == "_" {
+			continue
+		}
+		if prev, ok := c.scopes[len(c.scopes)-1][b.Name]; ok {
+			c.errorAt(b.Pos, len(b.Name), ErrRedefinition,
+				fmt.Sprintf(`Variable "%s" is already defined in this pattern.`, b.Name),
+				fmt.Sprintf("the previous definition is at %d:%d", prev.pos.row, prev.pos.col))
+		}
+		bindingType := ""
+		if payload != nil {
+			bindingType = payload[i]
+		}
+		b.setType(bindingType)
+		c.define(b.Name, &binding{typ: bindingType, pos: b.Pos, what: "a pattern variable"})
+	}
+}
+
+func (c *Checker) checkIndex(i *IndexAST) string {
+	typ := c.checkExpr(i.Array)
+	if indexType := c.checkExpr(i.Index); indexType != "" && indexType != LitInt {
+		c.errorAt(i.Index.Position(), 1, ErrTypeMismatch,
+			fmt.Sprintf("Index must be int, found %s.", indexType))
+	}
+	if typ == "" {
+		return ""
+	}
+
+	elem, _, ok := elemType(typ)
+	if !ok {
+		c.errorAt(i.Pos, 1, ErrTypeMismatch, fmt.Sprintf("Can't index a value of type %s.", typ))
+		return ""
+	}
+	i.setType(elem)
+	return elem
+}
+
+func (c *Checker) checkBinary(b *BinaryAST) string {
+	lType := c.checkExpr(b.Lhs)
+	rType := c.checkExpr(b.Rhs)
+	if lType == "" || rType == "" {
+		return ""
+	}
+
+	// User defined operators take exactly the types of the operands
+	for _, proto := range c.overloads["binary_"+b.Op] {
+		if len(proto.Args) == 2 && proto.Args[0].ArgType == lType && proto.Args[1].ArgType == rType {
+			b.Func = proto.Name
+			b.setType(c.returnType(proto, b.Pos, len(b.Op)))
+			return b.Type()
+		}
+	}
+
+	// Literals take the numeric type of the other side, and the narrower side
+	// of numbers converts to the type of the other
+	switch {
+	case lType == rType:
+	case adapts(b.Rhs, lType) || widens(lType, rType):
+		rType = c.convert(&b.Rhs, lType)
+	case adapts(b.Lhs, rType) || widens(rType, lType):
+		lType = c.convert(&b.Lhs, rType)
+	}
+
+	if lType != rType {
+		var notes []string
+		if b.Op == "+" && (lType == LitString && rType == LitChar || lType == LitChar && rType == LitString) {
+			notes = append(notes, "str(c) turns a char into a string")
+		}
+		c.errorAt(b.Pos, len(b.Op), ErrTypeMismatch,
+			fmt.Sprintf("Left and right side of the binary operator '%s' don't have the same type (%s and %s).", b.Op, lType, rType), notes...)
+		return ""
+	}
+
+	compares, ok := builtinOps[lType][b.Op]
+	if !ok {
+		c.errorAt(b.Pos, len(b.Op), ErrInvalidOperator,
+			fmt.Sprintf("Operator '%s' can't be used with %s.", b.Op, lType))
+		return ""
+	}
+
+	if compares {
+		b.setType(LitBool)
+	} else {
+		b.setType(lType)
+	}
+	return b.Type()
+}
+
+func (c *Checker) checkCast(e *CastAST) string {
+	typ := c.checkExpr(e.Value)
+	if !c.checkType(e.To, e.ToPos, false) || typ == "" {
+		return ""
+	}
+
+	from, fromOk := castType(typ)
+	to, toOk := castType(e.To)
+	if typ != e.To && (!fromOk || !toOk || (typ == LitChar || e.To == LitChar) && (from.float || to.float)) {
+		c.errorAt(e.ToPos, len(e.To), ErrTypeMismatch, fmt.Sprintf("Can't cast %s to %s.", typ, e.To),
+			"only numbers and chars can be cast, and chars only to and from integers")
+		return ""
+	}
+
+	e.setType(e.To)
+	return e.To
+}
+
+// convert turns the checked expression into a value of type to where that
+// needs no cast in the source: literals take the type, and numbers widening
+// to it are wrapped in an implicit cast. It returns the resulting type.
+func (c *Checker) convert(expr *AST, to string) string {
+	from := typeOf(*expr)
+	switch {
+	case from == to || from == "":
+	case adapts(*expr, to):
+		retype(*expr, to)
+	case widens(to, from):
+		cast := &CastAST{Pos: (*expr).Position(), kind: astCast, Value: *expr, To: to, Implicit: true}
+		cast.setType(to)
+		*expr = cast
+	}
+	return typeOf(*expr)
+}
+
+// adapts tells whether the expression is made only of number literals, or
+// arrays of them, that can be values of type to as well.
+func adapts(expr AST, to string) bool {
+	if a, ok := expr.(*ArrayAST); ok {
+		elem, n, ok := elemType(to)
+		if !ok || n >= 0 && n != len(a.Elements) {
+			return false
+		}
+		for _, e := range a.Elements {
+			if typeOf(e) != elem && !adapts(e, elem) {
+				return false
+			}
+		}
+		return true
+	}
+
+	info, ok := numberTypes[to]
+	if !ok {
+		return false
+	}
+	switch e := expr.(type) {
+	case *NumberLiteralAST:
+		return info.float || e.Kind() == astNumberInt
+	case *UnaryAST:
+		return e.Func == "" && builtinUnaryOps[to][string(rune(e.Operator))] && adapts(e.Operand, to)
+	case *BinaryAST:
+		compares, ok := builtinOps[to][e.Op]
+		return e.Func == "" && ok && !compares && adapts(e.Lhs, to) && adapts(e.Rhs, to)
+	}
+	return false
+}
+
+// adaptsAll tells whether each of the checked expressions is of type to
+// already or adapts to it.
+func adaptsAll(exprs []AST, to string) bool {
+	for _, expr := range exprs {
+		if typ := typeOf(expr); typ != "" && typ != to && !adapts(expr, to) {
+			return false
+		}
+	}
+	return true
+}
+
+// retypeAll gives the expressions adaptsAll accepts the type to.
+func retypeAll(exprs []AST, to string) {
+	for _, expr := range exprs {
+		if typ := typeOf(expr); typ != "" && typ != to {
+			retype(expr, to)
+		}
+	}
+}
+
+// retype gives an expression adapts accepts the type to. Whether its
+// literals are values of it is checked with the rest of the statement.
+func retype(expr AST, to string) {
+	switch e := expr.(type) {
+	case *NumberLiteralAST:
+		e.setType(to)
+	case *UnaryAST:
+		retype(e.Operand, to)
+		e.setType(to)
+	case *BinaryAST:
+		retype(e.Lhs, to)
+		retype(e.Rhs, to)
+		e.setType(to)
+	case *ArrayAST:
+		elem, _, _ := elemType(to)
+		for _, element := range e.Elements {
+			if typeOf(element) != elem {
+				retype(element, elem)
+			}
+		}
+		e.setType(arrayType(elem, len(e.Elements)))
+	}
+}
+
+// checkLiterals reports the number literals checked since the last call that
+// aren't values of the type they ended up with. It runs once a statement is
+// checked, when no context can change their types anymore.
+func (c *Checker) checkLiterals() {
+	for _, lit := range c.literals {
+		value := lit.Value
+		if c.negated[lit] {
+			value = -value
+		}
+		if info, ok := numberTypes[lit.Type()]; ok && !info.holds(value) {
+			c.errorAt(lit.Pos, len(numberText(lit.Value)), ErrTypeMismatch,
+				fmt.Sprintf("Number %s doesn't fit in %s.", numberText(value), lit.Type()))
+		}
+	}
+	c.literals = c.literals[:0]
+}
+
+func (c *Checker) checkUnary(u *UnaryAST) string {
+	typ := c.checkExpr(u.Operand)
+	if typ == "" {
+		return ""
+	}
+	if lit, ok := u.Operand.(*NumberLiteralAST); ok && u.Operator == '-' {
+		c.negated[lit] = true
+	}
+
+	op := string(rune(u.Operator))
+	for _, proto := range c.overloads["unary_"+op] {
+		if len(proto.Args) == 1 && proto.Args[0].ArgType == typ {
+			u.Func = proto.Name
+			u.setType(c.returnType(proto, u.Pos, 1))
+			return u.Type()
+		}
+	}
+
+	if builtinUnaryOps[typ][op] {
+		u.setType(typ)
+		return typ
+	}
+
+	c.errorAt(u.Pos, 1, ErrInvalidOperator, fmt.Sprintf("Unary operator '%s' can't be used with %s.", op, typ))
+	return ""
+}
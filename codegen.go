@@ -1,3 +1,6 @@
+//go:build llvm
+// +build llvm
+
 package main
 
 import (
@@ -7,15 +10,34 @@ import (
 )
 
 var (
+	ctx           llvm.Context
 	module        llvm.Module
-	builder       = llvm.NewBuilder()
+	builder       llvm.Builder
 	namedValues   = map[string]llvm.Value{}
 	fcPassManager llvm.PassManager
+	optLevel      int
+	structTypes   map[string]llvm.Type // of structs and enums
+	enumDecls     map[string]*EnumAST
 )
 
-func InitModuleAndPassManager() {
-	module = llvm.NewModule("novumroot")
+// codegenAST is the part of AST implemented by code generation. Builds
+// without the llvm tag leave it empty.
+type codegenAST interface {
+	codegen() llvm.Value
+}
+
+// InitModuleAndPassManager starts a new module. It has a context of its own,
+// so the named types of an earlier module don't rename the ones it declares.
+func InitModuleAndPassManager(level int) {
+	optLevel = level
+	ctx = llvm.NewContext()
+	module = ctx.NewModule("novumroot")
+	builder = ctx.NewBuilder()
+	structTypes = map[string]llvm.Type{}
+	enumDecls = map[string]*EnumAST{}
+	externABIs = map[string]*externABI{}
 	fcPassManager = llvm.NewFunctionPassManagerForModule(module)
+	fcPassManager.AddPromoteMemoryToRegisterPass()
 	fcPassManager.AddInstructionCombiningPass()
 	fcPassManager.AddGVNPass()
 	fcPassManager.AddCFGSimplificationPass()
@@ -23,109 +45,579 @@ func InitModuleAndPassManager() {
 	fcPassManager.InitializeFunc()
 }
 
+func verifyModule() error {
+	return llvm.VerifyModule(module, llvm.PrintMessageAction)
+}
+
+// optimizeModule runs the module level passes. Function passes already ran
+// while each function was generated.
+func optimizeModule(level int) {
+	if level < 2 {
+		return
+	}
+
+	pmb := llvm.NewPassManagerBuilder()
+	defer pmb.Dispose()
+	pmb.SetOptLevel(level)
+
+	pm := llvm.NewPassManager()
+	defer pm.Dispose()
+	pmb.Populate(pm)
+	pm.Run(module)
+}
+
+func writeBitcode(path string) error {
+	if path == "-" {
+		return llvm.WriteBitcodeToFile(module, os.Stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return llvm.WriteBitcodeToFile(module, file)
+}
+
+// llvmType maps a checked type to its LLVM representation.
+func llvmType(typ string) llvm.Type {
+	if t, ok := structTypes[typ]; ok {
+		return t
+	}
+
+	// Arrays are a pointer to the elements and their count
+	if elem, _, ok := elemType(typ); ok {
+		return ctx.StructType([]llvm.Type{llvm.PointerType(llvmType(elem), 0), ctx.Int32Type()}, false)
+	}
+
+	if info, ok := numberTypes[typ]; ok {
+		return numberLLVMType(info)
+	}
+
+	switch typ {
+	case LitString:
+		return llvm.PointerType(ctx.Int8Type(), 0)
+	case LitBool:
+		return ctx.Int1Type()
+	case LitChar:
+		return ctx.Int8Type()
+	case LitAtom:
+		return llvm.PointerType(ctx.Int8Type(), 0)
+	case LitVoid:
+		return ctx.VoidType()
+	}
+
+	panic(fmt.Sprintf("type-%s-does-no-exit", typ))
+}
+
+func numberLLVMType(info numberType) llvm.Type {
+	switch {
+	case info.float && info.bits == 32:
+		return ctx.FloatType()
+	case info.float:
+		return ctx.DoubleType()
+	case info.bits == 8:
+		return ctx.Int8Type()
+	case info.bits == 16:
+		return ctx.Int16Type()
+	case info.bits == 32:
+		return ctx.Int32Type()
+	default:
+		return ctx.Int64Type()
+	}
+}
+
+// createEntryBlockAlloca allocates a variable in the entry block of the
+// function, where the mem2reg pass can promote it to a register.
+func createEntryBlockAlloca(fc llvm.Value, typ llvm.Type, name string) llvm.Value {
+	tmpBuilder := ctx.NewBuilder()
+	defer tmpBuilder.Dispose()
+
+	entry := fc.EntryBasicBlock()
+	if first := entry.FirstInstruction(); first.IsNil() {
+		tmpBuilder.SetInsertPointAtEnd(entry)
+	} else {
+		tmpBuilder.SetInsertPointBefore(first)
+	}
+
+	return tmpBuilder.CreateAlloca(typ, name)
+}
+
 func (s *StringAST) codegen() llvm.Value {
 	return builder.CreateGlobalStringPtr(s.Value, "strtmp")
 }
 
+// AtomAST.codegen returns the address of the global holding the name of the
+// atom. Each atom has one global, so equal atoms have equal addresses.
+func (a *AtomAST) codegen() llvm.Value {
+	name := "atom." + a.Value
+	global := module.NamedGlobal(name)
+	if global.IsNil() {
+		value := ctx.ConstString(a.Value, true)
+		global = llvm.AddGlobal(module, value.Type(), name)
+		global.SetInitializer(value)
+		global.SetGlobalConstant(true)
+		global.SetLinkage(llvm.PrivateLinkage)
+	}
+	return llvm.ConstBitCast(global, llvm.PointerType(ctx.Int8Type(), 0))
+}
+
+func (c *CharAST) codegen() llvm.Value {
+	return llvm.ConstInt(ctx.Int8Type(), uint64(c.Value), false)
+}
+
+// NumberLiteralAST.codegen builds a constant of the type the checker gave the
+// literal, which can be a float type for an int literal.
 func (n *NumberLiteralAST) codegen() llvm.Value {
-	if n.Kind() == astNumberInt {
-		return llvm.ConstInt(llvm.Int32Type(), uint64(n.Value), false)
+	if numberTypes[n.Type()].float {
+		return llvm.ConstFloat(llvmType(n.Type()), n.Value)
 	}
+	if n.Value >= 0 {
+		return llvm.ConstInt(llvmType(n.Type()), uint64(n.Value), false)
+	}
+	return llvm.ConstInt(llvmType(n.Type()), uint64(int64(n.Value)), true)
+}
 
-	return llvm.ConstFloat(llvm.DoubleType(), n.Value)
+// CastAST.codegen picks the LLVM cast instruction from the representations
+// of both types. Types of the same width and kind, like int and i32, are the
+// same LLVM type and need none.
+func (c *CastAST) codegen() llvm.Value {
+	value := c.Value.codegen()
+	from, _ := castType(typeOf(c.Value))
+	to, _ := castType(c.To)
+	t := llvmType(c.To)
+
+	switch {
+	case from.float && to.float && to.bits < from.bits:
+		return builder.CreateFPTrunc(value, t, "casttmp")
+	case from.float && to.float && to.bits > from.bits:
+		return builder.CreateFPExt(value, t, "casttmp")
+	case from.float && to.float:
+		return value
+	case from.float && to.signed:
+		return builder.CreateFPToSI(value, t, "casttmp")
+	case from.float:
+		return builder.CreateFPToUI(value, t, "casttmp")
+	case to.float && from.signed:
+		return builder.CreateSIToFP(value, t, "casttmp")
+	case to.float:
+		return builder.CreateUIToFP(value, t, "casttmp")
+	case to.bits < from.bits:
+		return builder.CreateTrunc(value, t, "casttmp")
+	case to.bits > from.bits && from.signed:
+		return builder.CreateSExt(value, t, "casttmp")
+	case to.bits > from.bits:
+		return builder.CreateZExt(value, t, "casttmp")
+	}
+	return value
 }
 
 func (v *VariableAST) codegen() llvm.Value {
-	val, ok := namedValues[v.Name]
+	ptr, ok := namedValues[v.Name]
 
 	if !ok {
 		panic(fmt.Sprintf(`Variable "%s" does not exist!`, v.Name))
 	}
 
+	return builder.CreateLoad(ptr, v.Name)
+}
+
+func (v *VarDeclAST) codegen() llvm.Value {
+	val := v.Value.codegen()
+	fc := builder.GetInsertBlock().Parent()
+	ptr := createEntryBlockAlloca(fc, llvmType(v.VarType), v.Name)
+	builder.CreateStore(val, ptr)
+	namedValues[v.Name] = ptr
+
+	return val
+}
+
+func (a *AssignAST) codegen() llvm.Value {
+	val := a.Value.codegen()
+	builder.CreateStore(val, addressOf(a.Target))
 	return val
 }
 
+// addressOf returns the address of a variable, an array element or a struct
+// field for storing into it.
+func addressOf(target AST) llvm.Value {
+	switch target := target.(type) {
+	case *VariableAST:
+		ptr, ok := namedValues[target.Name]
+		if !ok {
+			panic(fmt.Sprintf(`Variable "%s" does not exist!`, target.Name))
+		}
+		return ptr
+	case *IndexAST:
+		return target.elementPtr()
+	case *FieldAST:
+		return builder.CreateStructGEP(addressOf(target.Struct), target.Index, target.Name)
+	}
+	panic(fmt.Sprintf("Can't assign to %T", target))
+}
+
+// declareStructs creates the named types of all structs and enums before
+// their fields, which can be types declared later.
+func declareStructs(decls []AST) {
+	for _, decl := range decls {
+		switch d := decl.(type) {
+		case *StructAST:
+			structTypes[d.Name] = ctx.StructCreateNamed("struct." + d.Name)
+		case *EnumAST:
+			structTypes[d.Name] = ctx.StructCreateNamed("enum." + d.Name)
+			enumDecls[d.Name] = d
+		}
+	}
+	for _, decl := range decls {
+		switch d := decl.(type) {
+		case *StructAST:
+			d.codegen()
+		case *EnumAST:
+			d.codegen()
+		}
+	}
+}
+
+// StructAST.codegen fills in the fields of the struct type. Declarations have
+// no value.
+func (s *StructAST) codegen() llvm.Value {
+	fields := make([]llvm.Type, 0, len(s.Fields))
+	for _, field := range s.Fields {
+		fields = append(fields, llvmType(field.ArgType))
+	}
+	structTypes[s.Name].StructSetBody(fields, false)
+	return llvm.Value{}
+}
+
+func (l *StructLitAST) codegen() llvm.Value {
+	s := llvm.Undef(llvmType(l.Name))
+	for _, field := range l.Fields {
+		s = builder.CreateInsertValue(s, field.Value.codegen(), field.Index, "")
+	}
+	return s
+}
+
+func (f *FieldAST) codegen() llvm.Value {
+	return builder.CreateExtractValue(f.Struct.codegen(), f.Index, f.Name)
+}
+
+// EnumAST.codegen fills in the enum type: the i32 tag of the variant, then
+// the payloads of all variants one after the other. They don't share memory
+// like a C union, so no value has to be reinterpreted.
+func (e *EnumAST) codegen() llvm.Value {
+	fields := []llvm.Type{ctx.Int32Type()}
+	for _, variant := range e.Variants {
+		for _, typ := range variant.Payload {
+			fields = append(fields, llvmType(typ))
+		}
+	}
+	structTypes[e.Name].StructSetBody(fields, false)
+	return llvm.Value{}
+}
+
+// payloadIndex returns the field of the enum type holding value i of the
+// payload of the variant with the tag.
+func payloadIndex(enum string, tag, i int) int {
+	index := 1
+	for _, variant := range enumDecls[enum].Variants[:tag] {
+		index += len(variant.Payload)
+	}
+	return index + i
+}
+
+func (v *VariantAST) codegen() llvm.Value {
+	tag := llvm.ConstInt(ctx.Int32Type(), uint64(v.Tag), false)
+	value := builder.CreateInsertValue(llvm.Undef(llvmType(v.Enum)), tag, 0, "")
+	for i, arg := range v.Args {
+		value = builder.CreateInsertValue(value, arg.codegen(), payloadIndex(v.Enum, v.Tag, i), "")
+	}
+	return value
+}
+
+// MatchAST.codegen jumps to the block of the matching arm, then generates
+// the arms, whose values meet in a phi.
+func (m *MatchAST) codegen() llvm.Value {
+	value := m.Value.codegen()
+	fc := builder.GetInsertBlock().Parent()
+
+	// Arms after '_' never run
+	arms := m.Arms
+	for i := range arms {
+		if arms[i].wildcard() {
+			arms = arms[:i+1]
+			break
+		}
+	}
+	blocks := make([]llvm.BasicBlock, len(arms))
+	for i := range arms {
+		blocks[i] = ctx.AddBasicBlock(fc, "matcharm")
+	}
+
+	switch typeOf(m.Value) {
+	case LitString, LitAtom:
+		compareCodegen(value, arms, blocks)
+	default:
+		key := value
+		if _, ok := enumDecls[typeOf(m.Value)]; ok {
+			key = builder.CreateExtractValue(value, 0, "tag")
+		}
+		switchCodegen(key, arms, blocks)
+	}
+
+	exitBlock := ctx.AddBasicBlock(fc, "matchexit")
+	var values []llvm.Value
+	var incoming []llvm.BasicBlock
+	for i := range arms {
+		builder.SetInsertPointAtEnd(blocks[i])
+		result := arms[i].codegen(value)
+		if blockTerminated() {
+			continue
+		}
+		if m.Type() != LitVoid {
+			values = append(values, result)
+			incoming = append(incoming, builder.GetInsertBlock())
+		}
+		builder.CreateBr(exitBlock)
+	}
+
+	exitBlock.MoveAfter(builder.GetInsertBlock())
+	builder.SetInsertPointAtEnd(exitBlock)
+	if len(values) == 0 {
+		return llvm.Value{}
+	}
+	phi := builder.CreatePHI(llvmType(m.Type()), "matchtmp")
+	phi.AddIncoming(values, incoming)
+	return phi
+}
+
+// switchCodegen dispatches on the tag of an enum or on an int with a switch.
+// '_' is the default destination, an exhaustive match without it never gets
+// there.
+func switchCodegen(key llvm.Value, arms []MatchArm, blocks []llvm.BasicBlock) {
+	// Like a value of an enum without variants
+	if len(arms) == 0 {
+		builder.CreateUnreachable()
+		return
+	}
+
+	last := len(arms) - 1
+	defaultBlock := blocks[last]
+	if !arms[last].wildcard() {
+		defaultBlock = ctx.AddBasicBlock(builder.GetInsertBlock().Parent(), "matchdefault")
+	}
+
+	sw := builder.CreateSwitch(key, defaultBlock, len(arms))
+	for i, arm := range arms {
+		switch {
+		case arm.wildcard():
+		case arm.Variant != "":
+			sw.AddCase(llvm.ConstInt(ctx.Int32Type(), uint64(arm.Tag), false), blocks[i])
+		default:
+			sw.AddCase(arm.Literal.codegen(), blocks[i])
+		}
+	}
+
+	if !arms[last].wildcard() {
+		builder.SetInsertPointAtEnd(defaultBlock)
+		builder.CreateUnreachable()
+	}
+}
+
+// compareCodegen compares a str or an atom with the pattern of each arm in
+// turn, since only ints can be switched on. Strings are compared by their
+// contents and atoms by their address.
+func compareCodegen(value llvm.Value, arms []MatchArm, blocks []llvm.BasicBlock) {
+	fc := builder.GetInsertBlock().Parent()
+	for i, arm := range arms {
+		if arm.wildcard() {
+			builder.CreateBr(blocks[i])
+			return
+		}
+
+		var equal llvm.Value
+		if _, ok := arm.Literal.(*StringAST); ok {
+			str := llvm.PointerType(ctx.Int8Type(), 0)
+			strcmp := libcFunction("strcmp", ctx.Int32Type(), str, str)
+			cmp := builder.CreateCall(strcmp, []llvm.Value{value, arm.Literal.codegen()}, "strcmptmp")
+			equal = builder.CreateICmp(llvm.IntEQ, cmp, llvm.ConstInt(ctx.Int32Type(), 0, false), "cmptmp")
+		} else {
+			equal = builder.CreateICmp(llvm.IntEQ, value, arm.Literal.codegen(), "cmptmp")
+		}
+
+		next := ctx.AddBasicBlock(fc, "matchnext")
+		builder.CreateCondBr(equal, blocks[i], next)
+		builder.SetInsertPointAtEnd(next)
+	}
+	builder.CreateUnreachable()
+}
+
+// MatchArm.codegen binds the payload of the matched variant and generates the
+// body of the arm.
+func (a *MatchArm) codegen(value llvm.Value) llvm.Value {
+	outer := namedValues
+	namedValues = make(map[string]llvm.Value, len(outer))
+	for name, ptr := range outer {
+		namedValues[name] = ptr
+	}
+	defer func() { namedValues = outer }()
+
+	fc := builder.GetInsertBlock().Parent()
+	for i, b := range a.Bindings {
+		if b.Name == "_" {
+			continue
+		}
+		ptr := createEntryBlockAlloca(fc, llvmType(b.Type()), b.Name)
+		builder.CreateStore(builder.CreateExtractValue(value, payloadIndex(a.Enum, a.Tag, i), b.Name), ptr)
+		namedValues[b.Name] = ptr
+	}
+
+	if a.Block != nil {
+		a.Block.codegen()
+		return llvm.Value{}
+	}
+	return a.Value.codegen()
+}
+
+// ArrayAST.codegen allocates the elements with malloc, like concatenated
+// strings they are never freed. The C library's malloc is called directly,
+// LLVM's array malloc would declare it with an i32 size.
+func (a *ArrayAST) codegen() llvm.Value {
+	elem, length, _ := elemType(a.Type())
+	t := llvmType(elem)
+	targetData := llvm.NewTargetData(module.DataLayout())
+	size := llvm.ConstInt(sizeType(), targetData.TypeAllocSize(t)*uint64(length), false)
+	targetData.Dispose()
+
+	malloc := libcFunction("malloc", llvm.PointerType(ctx.Int8Type(), 0), sizeType())
+	elements := builder.CreateBitCast(builder.CreateCall(malloc, []llvm.Value{size}, ""), llvm.PointerType(t, 0), "elements")
+	count := llvm.ConstInt(ctx.Int32Type(), uint64(length), false)
+	for i, e := range a.Elements {
+		ptr := builder.CreateInBoundsGEP(elements, []llvm.Value{llvm.ConstInt(ctx.Int32Type(), uint64(i), false)}, "")
+		builder.CreateStore(e.codegen(), ptr)
+	}
+
+	array := builder.CreateInsertValue(llvm.Undef(llvmType(a.Type())), elements, 0, "")
+	return builder.CreateInsertValue(array, count, 1, "array")
+}
+
+func (i *IndexAST) codegen() llvm.Value {
+	return builder.CreateLoad(i.elementPtr(), "elem")
+}
+
+// elementPtr returns the address of the element after checking the index.
+func (i *IndexAST) elementPtr() llvm.Value {
+	array := i.Array.codegen()
+	index := i.Index.codegen()
+
+	// Negative indexes are huge unsigned ones
+	length := builder.CreateExtractValue(array, 1, "len")
+	panicIf(builder.CreateICmp(llvm.IntUGE, index, length, "outofrange"), indexOutOfRange(i.Pos))
+
+	elements := builder.CreateExtractValue(array, 0, "elements")
+	return builder.CreateInBoundsGEP(elements, []llvm.Value{index}, "")
+}
+
+var intPredicates = map[string]llvm.IntPredicate{
+	"==": llvm.IntEQ,
+	"!=": llvm.IntNE,
+	"<":  llvm.IntSLT,
+	"<=": llvm.IntSLE,
+	">":  llvm.IntSGT,
+	">=": llvm.IntSGE,
+}
+
+// unsignedPredicates compare chars, which are bytes from 0 to 255.
+var unsignedPredicates = map[string]llvm.IntPredicate{
+	"==": llvm.IntEQ,
+	"!=": llvm.IntNE,
+	"<":  llvm.IntULT,
+	"<=": llvm.IntULE,
+	">":  llvm.IntUGT,
+	">=": llvm.IntUGE,
+}
+
+var floatPredicates = map[string]llvm.FloatPredicate{
+	"==": llvm.FloatOEQ,
+	"!=": llvm.FloatONE,
+	"<":  llvm.FloatOLT,
+	"<=": llvm.FloatOLE,
+	">":  llvm.FloatOGT,
+	">=": llvm.FloatOGE,
+}
+
+// shiftCount keeps the shift count below the width of the integer. Larger
+// counts would make LLVM produce poison values.
+func shiftCount(r llvm.Value, info numberType) llvm.Value {
+	return builder.CreateAnd(r, llvm.ConstInt(r.Type(), uint64(info.bits-1), false), "shiftcount")
+}
+
 func (b *BinaryAST) binOpNumberCodegen(l, r llvm.Value, kind string) llvm.Value {
 	if l.IsNil() || r.IsNil() {
 		panic("null operands")
 	}
 
+	info := numberTypes[kind]
+	isInt := !info.float
+	if isInt && info.signed {
+		if pred, ok := intPredicates[b.Op]; ok {
+			return builder.CreateICmp(pred, l, r, "cmptmp")
+		}
+	} else if isInt {
+		if pred, ok := unsignedPredicates[b.Op]; ok {
+			return builder.CreateICmp(pred, l, r, "cmptmp")
+		}
+	} else if pred, ok := floatPredicates[b.Op]; ok {
+		return builder.CreateFCmp(pred, l, r, "cmptmp")
+	}
+
 	switch b.Op {
 	case "+":
-		if kind == LitInt {
+		if isInt {
 			return builder.CreateAdd(l, r, "addtmp")
 		}
 		return builder.CreateFAdd(l, r, "addtmp")
 	case "-":
-		if kind == LitInt {
-			return builder.CreateSub(l, r, "addtmp")
+		if isInt {
+			return builder.CreateSub(l, r, "subtmp")
 		}
 		return builder.CreateFSub(l, r, "subtmp")
 	case "*":
-		if kind == LitInt {
-			return builder.CreateMul(l, r, "addtmp")
+		if isInt {
+			return builder.CreateMul(l, r, "multmp")
 		}
-		return builder.CreateFMul(l, r, "multmo")
+		return builder.CreateFMul(l, r, "multmp")
 	case "/":
-		if kind == LitInt {
+		checkDivisor(r, kind)
+		if isInt && info.signed {
 			return builder.CreateSDiv(l, r, "divtmp")
+		} else if isInt {
+			return builder.CreateUDiv(l, r, "divtmp")
 		}
-		if os.Getenv("PRELUDE") == "empty" {
-			return builder.CreateFDiv(l, r, "divtmp")
-		}
-		cond := builder.CreateFCmp(llvm.FloatOEQ, llvm.ConstFloat(llvm.DoubleType(), 0), r, "cmptmp")
-		fc := builder.GetInsertBlock().Parent()
-		thenBlock := llvm.AddBasicBlock(fc, "thendivchecker")
-		elseBlock := llvm.AddBasicBlock(fc, "elsedivchecker")
-		exitBlock := llvm.AddBasicBlock(fc, "exitdivchecker")
-		builder.CreateCondBr(cond, thenBlock, elseBlock)
-
-		builder.SetInsertPointAtEnd(thenBlock)
-		calleePanic := module.NamedFunction("printf")
-		if calleePanic.IsNil() {
-			panic(fmt.Sprintf(`Function printf could not be referenced`))
-		}
-
-		panicMesssage := builder.CreateGlobalStringPtr("Panic: right side of the equation is equal to 0\n", "")
-		argsValues := []llvm.Value{panicMesssage}
-		builder.CreateCall(calleePanic, argsValues, "")
-
-		calleeExit := module.NamedFunction("exit")
-		if calleeExit.IsNil() {
-			panic(fmt.Sprintf(`Function exit could not be referenced`))
-		}
-
-		builder.CreateCall(calleeExit, []llvm.Value{llvm.ConstInt(llvm.Int32Type(), 0, false)}, "")
-		builder.CreateBr(exitBlock)
-
-		builder.SetInsertPointAtEnd(elseBlock)
-		result := builder.CreateFDiv(l, r, "divtmp")
-		builder.CreateBr(exitBlock)
-
-		builder.SetInsertPointAtEnd(exitBlock)
-		return result
-	case "<":
-		if kind == LitInt {
-			return builder.CreateICmp(llvm.IntSLT, l, r, "addtmp")
-		}
-		return builder.CreateFCmp(llvm.FloatOLT, l, r, "cmptmp")
-	case ">":
-		if kind == LitInt {
-			return builder.CreateICmp(llvm.IntSLT, r, l, "addtmp")
+		return builder.CreateFDiv(l, r, "divtmp")
+	case "%":
+		checkDivisor(r, kind)
+		if isInt && info.signed {
+			return builder.CreateSRem(l, r, "remtmp")
+		} else if isInt {
+			return builder.CreateURem(l, r, "remtmp")
 		}
-		return builder.CreateFCmp(llvm.FloatOLT, l, r, "cmptmp")
-	case "==":
-		if kind == LitInt {
-			return builder.CreateICmp(llvm.IntEQ, l, r, "addtmp")
+		return builder.CreateFRem(l, r, "remtmp")
+	case "&":
+		return builder.CreateAnd(l, r, "andtmp")
+	case "|":
+		return builder.CreateOr(l, r, "ortmp")
+	case "^":
+		return builder.CreateXor(l, r, "xortmp")
+	case "<<":
+		return builder.CreateShl(l, shiftCount(r, info), "shltmp")
+	case ">>":
+		if info.signed {
+			return builder.CreateAShr(l, shiftCount(r, info), "shrtmp")
 		}
-		return builder.CreateFCmp(llvm.FloatOEQ, l, r, "cmptmp")
-	case "!=":
-		if kind == LitInt {
-			return builder.CreateICmp(llvm.IntNE, l, r, "addtmp")
-		}
-		return builder.CreateFCmp(llvm.FloatONE, l, r, "cmptmp")
+		return builder.CreateLShr(l, shiftCount(r, info), "shrtmp")
 	default:
-		panic(fmt.Sprintf(`Operator "%c" is invalid`, b.Op))
+		panic(fmt.Sprintf(`Operator "%s" is invalid`, b.Op))
 	}
 }
 
@@ -134,17 +626,20 @@ func (b *BinaryAST) binOpStrCodegen(l, r llvm.Value) llvm.Value {
 		panic("null operands")
 	}
 
-	switch b.Op {
-	case "+":
-		// Todo figure out string concat
-		panic("not implemented: String Concat")
-	case "==":
-		l = builder.CreatePointerCast(l, llvm.Int8Type(), "pointcast")
-		r = builder.CreatePointerCast(r, llvm.Int8Type(), "pointcast")
-		return builder.CreateICmp(llvm.IntEQ, l, r, "cmptmp")
-	default:
-		panic(fmt.Sprintf(`Operator "%c" is invalid`, b.Op))
+	if b.Op == "+" {
+		return builder.CreateCall(strConcatFunction(), []llvm.Value{l, r}, "concattmp")
+	}
+
+	pred, ok := intPredicates[b.Op]
+	if !ok {
+		panic(fmt.Sprintf(`Operator "%s" is invalid`, b.Op))
 	}
+
+	// Strings are compared by contents, identical() compares the pointers
+	str := llvm.PointerType(ctx.Int8Type(), 0)
+	strcmp := libcFunction("strcmp", ctx.Int32Type(), str, str)
+	cmp := builder.CreateCall(strcmp, []llvm.Value{l, r}, "strcmptmp")
+	return builder.CreateICmp(pred, cmp, llvm.ConstInt(ctx.Int32Type(), 0, false), "cmptmp")
 }
 
 func (b *BinaryAST) binOpBoolCodegen(l, r llvm.Value) llvm.Value {
@@ -157,52 +652,78 @@ func (b *BinaryAST) binOpBoolCodegen(l, r llvm.Value) llvm.Value {
 		return builder.CreateICmp(llvm.IntNE, l, r, "cmptmp")
 	case "==":
 		return builder.CreateICmp(llvm.IntEQ, l, r, "cmptmp")
+	case "&":
+		return builder.CreateAnd(l, r, "andtmp")
+	case "|":
+		return builder.CreateOr(l, r, "ortmp")
+	case "^":
+		return builder.CreateXor(l, r, "xortmp")
 	default:
-		panic(fmt.Sprintf(`Operator "%c" is invalid`, b.Op))
+		panic(fmt.Sprintf(`Operator "%s" is invalid`, b.Op))
 	}
 }
 
-func (b *BinaryAST) codegen() llvm.Value {
+// logicalCodegen generates '&&' and '||'. The right side is only evaluated
+// when the left one doesn't decide the result already.
+func (b *BinaryAST) logicalCodegen() llvm.Value {
 	l := b.Lhs.codegen()
-	lKind := LLVMTypeToLit(l.Type())
+	lhsBlock := builder.GetInsertBlock()
+	fc := lhsBlock.Parent()
+	rhsBlock := ctx.AddBasicBlock(fc, "logicrhs")
+	exitBlock := ctx.AddBasicBlock(fc, "logicexit")
+
+	shortCircuit := llvm.ConstInt(ctx.Int1Type(), 0, false)
+	if b.Op == "&&" {
+		builder.CreateCondBr(l, rhsBlock, exitBlock)
+	} else {
+		shortCircuit = llvm.ConstInt(ctx.Int1Type(), 1, false)
+		builder.CreateCondBr(l, exitBlock, rhsBlock)
+	}
 
+	builder.SetInsertPointAtEnd(rhsBlock)
 	r := b.Rhs.codegen()
-	rKind := LLVMTypeToLit(r.Type())
+	rhsBlock = builder.GetInsertBlock()
+	builder.CreateBr(exitBlock)
 
-	binOp, ok := BinOpLookup["binary_"+b.Op]
-	if ok {
-		lOk := lKind == binOp[0].ArgType
-		rOk := rKind == binOp[1].ArgType
-
-		if lOk && rOk {
-			callee := module.NamedFunction("binary_" + b.Op)
-			if callee.IsNil() {
-				panic(fmt.Sprintf(`Function "%s" could not be referenced`, b.Op))
-			}
+	builder.SetInsertPointAtEnd(exitBlock)
+	phi := builder.CreatePHI(ctx.Int1Type(), "logictmp")
+	phi.AddIncoming([]llvm.Value{shortCircuit, r}, []llvm.BasicBlock{lhsBlock, rhsBlock})
+	return phi
+}
 
-			if callee.ParamsCount() != 2 {
-				panic(fmt.Sprintf(`Incorrect arguments passed in the function "%s"`, b.Op))
-			}
+func (b *BinaryAST) codegen() llvm.Value {
+	if b.Func == "" && (b.Op == "&&" || b.Op == "||") {
+		return b.logicalCodegen()
+	}
 
-			argsValues := []llvm.Value{l, r}
+	l := b.Lhs.codegen()
+	r := b.Rhs.codegen()
 
-			return builder.CreateCall(callee, argsValues, "")
+	if b.Func != "" {
+		callee := module.NamedFunction(b.Func)
+		if callee.IsNil() {
+			panic(fmt.Sprintf(`Function "%s" could not be referenced`, b.Func))
 		}
+
+		return builder.CreateCall(callee, []llvm.Value{l, r}, "")
 	}
 
-	if lKind != rKind {
-		panic("Error: Left and right side of the binary operator don't have the same type")
+	kind := typeOf(b.Lhs)
+	if _, ok := numberTypes[kind]; ok {
+		return b.binOpNumberCodegen(l, r, kind)
 	}
 
-	switch lKind {
-	case LitFloat, LitInt:
-		return b.binOpNumberCodegen(l, r, lKind)
+	switch kind {
 	case LitString:
 		return b.binOpStrCodegen(l, r)
 	case LitBool:
 		return b.binOpBoolCodegen(l, r)
+	case LitChar:
+		return builder.CreateICmp(unsignedPredicates[b.Op], l, r, "cmptmp")
+	case LitAtom:
+		return builder.CreateICmp(intPredicates[b.Op], l, r, "cmptmp")
 	default:
-		panic("Error: '" + lKind + "' cannot be used with binary operator")
+		panic("Error: '" + kind + "' cannot be used with binary operator")
 	}
 }
 
@@ -212,7 +733,18 @@ func (u *UnaryAST) codegen() llvm.Value {
 		panic("Error: Unary operand does not exist")
 	}
 
-	callee := module.NamedFunction("unary_" + string(rune(u.Operator)))
+	if u.Func == "" {
+		switch {
+		case u.Operator == '!':
+			return builder.CreateNot(operand, "nottmp")
+		case u.Operator == '-' && numberTypes[u.Type()].float:
+			return builder.CreateFNeg(operand, "negtmp")
+		case u.Operator == '-':
+			return builder.CreateNeg(operand, "negtmp")
+		}
+	}
+
+	callee := module.NamedFunction(u.Func)
 	if callee.IsNil() {
 		panic("Error: Unary operator '" + string(rune(u.Operator)) + "' does not exist")
 	}
@@ -221,13 +753,22 @@ func (u *UnaryAST) codegen() llvm.Value {
 }
 
 func (c *CallAST) codegen() llvm.Value {
+	if intrinsic, ok := intrinsicCodegen[c.Callee]; ok {
+		args := make([]llvm.Value, 0, len(c.args))
+		for _, arg := range c.args {
+			args = append(args, arg.codegen())
+		}
+		return intrinsic(args)
+	}
+
 	callee := module.NamedFunction(c.Callee)
 
 	if callee.IsNil() {
 		panic(fmt.Sprintf(`Function "%s" could not be referenced`, c.Callee))
 	}
 
-	if callee.ParamsCount() != len(c.args) {
+	abi := externABIs[c.Callee]
+	if abi == nil && callee.ParamsCount() != len(c.args) {
 		panic(fmt.Sprintf(`Incorrect arguments passed in the function "%s"`, c.Callee))
 	}
 
@@ -242,43 +783,24 @@ func (c *CallAST) codegen() llvm.Value {
 		argsValues = append(argsValues, argVal)
 	}
 
+	if abi != nil {
+		return abi.call(callee, argsValues)
+	}
 	return builder.CreateCall(callee, argsValues, "")
 }
 
 func (p *PrototypeAST) codegen() llvm.Value {
 	args := make([]llvm.Type, 0, len(p.Args))
-
 	for _, a := range p.Args {
-		switch a.ArgType {
-		case LitFloat:
-			args = append(args, llvm.DoubleType())
-		case LitString:
-			args = append(args, llvm.PointerType(llvm.Int8Type(), 0))
-		case LitBool:
-			args = append(args, llvm.Int1Type())
-		case LitInt:
-			args = append(args, llvm.Int32Type())
-		default:
-			panic(fmt.Sprintf("type-%s-does-no-exit", a.ArgType))
-		}
+		args = append(args, llvmType(a.ArgType))
 	}
-	var fcType llvm.Type
 
-	switch p.ReturnType {
-	case LitFloat:
-		fcType = llvm.FunctionType(llvm.DoubleType(), args, false)
-	case LitString:
-		fcType = llvm.FunctionType(llvm.PointerType(llvm.Int8Type(), 0), args, false)
-	case LitVoid:
-		fcType = llvm.FunctionType(llvm.VoidType(), args, false)
-	case LitInt:
-		fcType = llvm.FunctionType(llvm.Int32Type(), args, false)
-	case LitBool:
-		fcType = llvm.FunctionType(llvm.Int1Type(), args, false)
-	default:
-		panic(fmt.Sprintf("type-%s-does-no-exit", p.ReturnType))
+	ret := llvmType(p.ReturnType)
+	if p.Name == "main" && p.ReturnType == LitVoid {
+		ret = ctx.Int32Type()
 	}
 
+	fcType := llvm.FunctionType(ret, args, false)
 	fc := llvm.AddFunction(module, p.Name, fcType)
 
 	for i, param := range fc.Params() {
@@ -288,18 +810,30 @@ func (p *PrototypeAST) codegen() llvm.Value {
 	return fc
 }
 
-func (b *BlockAST) codegen() ([]llvm.Value, bool) {
-	elements := []llvm.Value{}
-	isReturn := false
+// blockTerminated tells whether the current block already ends with a
+// return or a branch, after which nothing may be added to it.
+func blockTerminated() bool {
+	last := builder.GetInsertBlock().LastInstruction()
+	return !last.IsNil() && !last.IsATerminatorInst().IsNil()
+}
+
+func (b *BlockAST) codegen() {
+	// Variables declared in the block go out of scope at its end
+	outer := namedValues
+	namedValues = make(map[string]llvm.Value, len(outer))
+	for name, ptr := range outer {
+		namedValues[name] = ptr
+	}
+	defer func() { namedValues = outer }()
+
 	for _, stmt := range b.Elements {
-		elements = append(elements, stmt.codegen())
-		if stmt.Kind() == astReturn {
-			isReturn = true
+		stmt.codegen()
+		// Code after return, break or continue can never run, the checker
+		// warned about it
+		if blockTerminated() {
 			break
 		}
 	}
-
-	return elements, isReturn
 }
 
 // TODO Check for redefinition
@@ -312,23 +846,32 @@ func (p *FunctionAST) codegen() llvm.Value {
 	if fc.IsNil() {
 		panic(fmt.Sprintf(`Could not create function "%s"`, p.Proto.Name))
 	}
-	block := llvm.AddBasicBlock(fc, "entry")
+	block := ctx.AddBasicBlock(fc, "entry")
 	builder.SetInsertPointAtEnd(block)
 
 	namedValues = map[string]llvm.Value{}
 
-	for _, param := range fc.Params() {
-		namedValues[param.Name()] = param
+	for i, param := range fc.Params() {
+		name := p.Proto.Args[i].Name
+		ptr := createEntryBlockAlloca(fc, param.Type(), name)
+		builder.CreateStore(param, ptr)
+		namedValues[name] = ptr
 	}
 
 	p.Body.codegen()
 
+	// The last block is left open when nothing jumps to it, like the exit of
+	// an if whose branches all return
+	if !blockTerminated() {
+		builder.CreateUnreachable()
+	}
+
 	if llvm.VerifyFunction(fc, llvm.PrintMessageAction) != nil {
 		fc.EraseFromParentAsFunction()
 		panic(fmt.Sprintf(`Error occurred while verifing function "%s"`, p.Proto.Name))
 	}
 
-	if os.Getenv("DEBUG") != "true" {
+	if optLevel > 0 {
 		fcPassManager.RunFunc(fc)
 	}
 
@@ -336,161 +879,215 @@ func (p *FunctionAST) codegen() llvm.Value {
 }
 
 func (i *IfElseAST) codegen() llvm.Value {
-	cond := i.Condition.codegen()
-	if cond.IsNil() {
-		panic("No condition")
-	}
-
 	fc := builder.GetInsertBlock().Parent()
-	thenBlock := llvm.AddBasicBlock(fc, "then")
-	elseBlock := llvm.AddBasicBlock(fc, "else")
-	exitBlock := llvm.AddBasicBlock(fc, "exit")
-
-	builder.CreateCondBr(cond, thenBlock, elseBlock)
+	exitBlock := ctx.AddBasicBlock(fc, "exit")
 
-	// build then body
-	builder.SetInsertPointAtEnd(thenBlock)
-	_, isRet := i.TrueBody.codegen()
-
-	if !isRet {
-		builder.CreateBr(exitBlock)
+	conditions := []AST{i.Condition}
+	bodies := []*BlockAST{&i.TrueBody}
+	for ind := range i.ElseIfBody {
+		conditions = append(conditions, i.ElseIfBody[ind].Condition)
+		bodies = append(bodies, &i.ElseIfBody[ind].Body)
 	}
 
-	// build elifs body
-	for ind, el := range i.ElseIfBody {
-		if ind == 0 {
-			builder.SetInsertPointAtEnd(elseBlock)
-			elseBlock = llvm.AddBasicBlock(fc, "else")
-		}
-
-		elifThenBlock := llvm.AddBasicBlock(fc, "then")
-		elifElseBlock := llvm.AddBasicBlock(fc, "else")
-
-		elifCond := el.Condition.codegen()
-		if elifCond.IsNil() {
-			panic("No condition in elif")
+	var first llvm.Value
+	for ind, condition := range conditions {
+		cond := condition.codegen()
+		if cond.IsNil() {
+			panic("No condition")
 		}
-
-		if ind == len(i.ElseIfBody)-1 {
-			builder.CreateCondBr(elifCond, elifThenBlock, elseBlock)
-		} else {
-			builder.CreateCondBr(elifCond, elifThenBlock, elifElseBlock)
+		if ind == 0 {
+			first = cond
 		}
 
-		builder.SetInsertPointAtEnd(elifThenBlock)
-		_, isRet := el.Body.codegen()
+		thenBlock := ctx.AddBasicBlock(fc, "then")
+		elseBlock := ctx.AddBasicBlock(fc, "else")
+		builder.CreateCondBr(cond, thenBlock, elseBlock)
 
-		if !isRet {
+		builder.SetInsertPointAtEnd(thenBlock)
+		bodies[ind].codegen()
+		if !blockTerminated() {
 			builder.CreateBr(exitBlock)
 		}
 
-		builder.SetInsertPointAtEnd(elifElseBlock)
-		if ind == len(i.ElseIfBody)-1 {
-			builder.CreateBr(exitBlock)
-		}
+		builder.SetInsertPointAtEnd(elseBlock)
 	}
 
-	// build else body
-	builder.SetInsertPointAtEnd(elseBlock)
-	_, isRet = i.ElseBody.codegen()
-
-	if !isRet {
+	i.ElseBody.codegen()
+	if !blockTerminated() {
 		builder.CreateBr(exitBlock)
 	}
 
+	exitBlock.MoveAfter(builder.GetInsertBlock())
 	builder.SetInsertPointAtEnd(exitBlock)
 
-	return cond
+	return first
 }
 
 func (r *ReturnAST) codegen() llvm.Value {
 	if r.Body == nil {
+		// main gives the exit status to the C runtime even when it is
+		// declared without a result
+		if builder.GetInsertBlock().Parent().Name() == "main" {
+			return builder.CreateRet(llvm.ConstInt(ctx.Int32Type(), 0, false))
+		}
 		return builder.CreateRetVoid()
 	}
 	return builder.CreateRet(r.Body.codegen())
 }
 
-func (l *LoopAST) codegen() llvm.Value {
-	cond := l.Condition.codegen()
-	if cond.IsNil() {
-		panic("No condition in the loop")
-	}
-
-	zeroInd := llvm.ConstInt(llvm.Int32Type(), 0, false)
-	var elemAlloca llvm.Value
-	var gep llvm.Value
-	var load llvm.Value
+// loopTarget is where break and continue jump to in an enclosing loop.
+type loopTarget struct {
+	label         string
+	continueBlock llvm.BasicBlock
+	breakBlock    llvm.BasicBlock
+}
 
-	if l.forIn {
-		elemAlloca = builder.CreateArrayAlloca(cond.Operand(0).Operand(0).Type().ElementType(), llvm.ConstInt(llvm.Int32Type(), 1, false), "")
-		gep = builder.CreateInBoundsGEP(cond, []llvm.Value{zeroInd}, "")
-		load = builder.CreateLoad(gep, "load")
-		builder.CreateStore(load, elemAlloca)
-	}
+// loops holds the loops enclosing the code being generated, innermost last.
+var loops []loopTarget
 
+func (l *LoopAST) codegen() llvm.Value {
 	fc := builder.GetInsertBlock().Parent()
-	headerBlock := builder.GetInsertBlock()
-	loopBlock := llvm.AddBasicBlock(fc, "loop")
-	exitBlock := llvm.AddBasicBlock(fc, "exitloop")
+	headerBlock := ctx.AddBasicBlock(fc, "loopheader")
+	loopBlock := ctx.AddBasicBlock(fc, "loop")
+	latchBlock := ctx.AddBasicBlock(fc, "looplatch")
+	exitBlock := ctx.AddBasicBlock(fc, "exitloop")
+
+	// The loop variables are only visible in the loop
+	outer := namedValues
+	namedValues = make(map[string]llvm.Value, len(outer))
+	for name, ptr := range outer {
+		namedValues[name] = ptr
+	}
+	defer func() { namedValues = outer }()
 
+	var indPtr llvm.Value
 	if l.forIn {
-		builder.CreateBr(loopBlock)
-	} else {
-		builder.CreateCondBr(builder.CreateICmp(llvm.IntNE, cond, llvm.ConstInt(llvm.Int1Type(), 0, false), "loopcond"), loopBlock, exitBlock)
+		indPtr = createEntryBlockAlloca(fc, ctx.Int32Type(), "ind")
+		builder.CreateStore(llvm.ConstInt(ctx.Int32Type(), 0, false), indPtr)
+		if l.IndexVar != "" {
+			namedValues[l.IndexVar] = indPtr
+		}
 	}
 
-	builder.SetInsertPointAtEnd(loopBlock)
-	valInd := builder.CreatePHI(llvm.Int32Type(), "ind")
-	valInd.AddIncoming([]llvm.Value{llvm.ConstInt(llvm.Int32Type(), 0, false)}, []llvm.BasicBlock{headerBlock})
+	// next is called in the latch to advance the element
+	next := func() {}
+	r, isRange := l.Condition.(*RangeAST)
+	switch {
+	case isRange:
+		// The element is the counter itself, loop variables can't be assigned
+		elemPtr := createEntryBlockAlloca(fc, ctx.Int32Type(), l.ElementVar)
+		namedValues[l.ElementVar] = elemPtr
+		builder.CreateStore(r.Start.codegen(), elemPtr)
+		end := r.End.codegen()
+		step := llvm.ConstInt(ctx.Int32Type(), 1, false)
+		if r.Step != nil {
+			step = r.Step.codegen()
+			panicIf(builder.CreateICmp(llvm.IntEQ, step, llvm.ConstInt(ctx.Int32Type(), 0, false), "zerostep"),
+				"step of the range is equal to 0")
+		}
+		builder.CreateBr(headerBlock)
 
-	// shadow variables with index and element
-	oldValInd, okInd := namedValues[l.IndexVar]
-	oldValElem, okElem := namedValues[l.ElementVar]
-	if l.forIn {
-		namedValues[l.IndexVar] = valInd
-		namedValues[l.ElementVar] = elemAlloca
-	}
-
-	// TODO Check if loop's body does not have return inside
-	_, isRet := l.Body.codegen()
-	if !isRet {
-		// Get next element from array and get next index
-		nextInd := builder.CreateAdd(valInd, llvm.ConstInt(llvm.Int32Type(), 1, false), "nextind")
-
-		var breakCond llvm.Value
-		if l.forIn {
-			gep = builder.CreateInBoundsGEP(cond, []llvm.Value{nextInd}, "")
-			load = builder.CreateLoad(gep, "load")
-			builder.CreateStore(load, elemAlloca)
-			breakCond = builder.CreateICmp(llvm.IntNE, llvm.ConstInt(llvm.Int32Type(), uint64(cond.Operand(0).Operand(0).Type().ArrayLength()), false), nextInd, "loopcond")
-		} else {
-			breakCond = builder.CreateICmp(llvm.IntNE, cond, llvm.ConstInt(llvm.Int1Type(), 0, false), "loopcond")
+		builder.SetInsertPointAtEnd(headerBlock)
+		upPred, downPred := llvm.IntSLT, llvm.IntSGT
+		if r.Inclusive {
+			upPred, downPred = llvm.IntSLE, llvm.IntSGE
+		}
+		val := builder.CreateLoad(elemPtr, l.ElementVar)
+		countsUp := builder.CreateICmp(llvm.IntSGT, step, llvm.ConstInt(ctx.Int32Type(), 0, false), "countsup")
+		cond := builder.CreateSelect(countsUp,
+			builder.CreateICmp(upPred, val, end, "beforeend"),
+			builder.CreateICmp(downPred, val, end, "afterend"), "loopcond")
+		builder.CreateCondBr(cond, loopBlock, exitBlock)
+		builder.SetInsertPointAtEnd(loopBlock)
+
+		next = func() {
+			val := builder.CreateLoad(elemPtr, l.ElementVar)
+			builder.CreateStore(builder.CreateAdd(val, step, "next"), elemPtr)
 		}
+	case l.forIn && typeOf(l.Condition) != LitString:
+		array := l.Condition.codegen()
+		elem, _, _ := elemType(typeOf(l.Condition))
+		elements := builder.CreateExtractValue(array, 0, "elements")
+		length := builder.CreateExtractValue(array, 1, "len")
+		elemPtr := createEntryBlockAlloca(fc, llvmType(elem), l.ElementVar)
+		namedValues[l.ElementVar] = elemPtr
+		builder.CreateBr(headerBlock)
+
+		builder.SetInsertPointAtEnd(headerBlock)
+		ind := builder.CreateLoad(indPtr, "ind")
+		builder.CreateCondBr(builder.CreateICmp(llvm.IntSLT, ind, length, "loopcond"), loopBlock, exitBlock)
+
+		builder.SetInsertPointAtEnd(loopBlock)
+		builder.CreateStore(builder.CreateLoad(builder.CreateInBoundsGEP(elements, []llvm.Value{ind}, ""), "elem"), elemPtr)
+	case l.forIn:
+		// The length is taken once, strings can't change
+		str := l.Condition.codegen()
+		strlen := libcFunction("strlen", sizeType(), str.Type())
+		length := builder.CreateTrunc(builder.CreateCall(strlen, []llvm.Value{str}, "len"), ctx.Int32Type(), "len")
+		elemPtr := createEntryBlockAlloca(fc, llvmType(LitChar), l.ElementVar)
+		namedValues[l.ElementVar] = elemPtr
+		builder.CreateBr(headerBlock)
+
+		builder.SetInsertPointAtEnd(headerBlock)
+		ind := builder.CreateLoad(indPtr, "ind")
+		builder.CreateCondBr(builder.CreateICmp(llvm.IntSLT, ind, length, "loopcond"), loopBlock, exitBlock)
+
+		builder.SetInsertPointAtEnd(loopBlock)
+		char := builder.CreateLoad(builder.CreateInBoundsGEP(str, []llvm.Value{ind}, ""), "char")
+		builder.CreateStore(char, elemPtr)
+	default:
+		builder.CreateBr(headerBlock)
+		builder.SetInsertPointAtEnd(headerBlock)
+		cond := l.Condition.codegen()
+		if cond.IsNil() {
+			panic("No condition in the loop")
+		}
+		builder.CreateCondBr(cond, loopBlock, exitBlock)
+		builder.SetInsertPointAtEnd(loopBlock)
+	}
 
-		loopExitBlock := builder.GetInsertBlock()
-		builder.CreateCondBr(breakCond, loopBlock, exitBlock)
-		builder.SetInsertPointAtEnd(exitBlock)
-		valInd.AddIncoming([]llvm.Value{nextInd}, []llvm.BasicBlock{loopExitBlock})
-	} else {
-		l.kind = astReturn
+	loops = append(loops, loopTarget{l.Label, latchBlock, exitBlock})
+	l.Body.codegen()
+	loops = loops[:len(loops)-1]
+	if !blockTerminated() {
+		builder.CreateBr(latchBlock)
 	}
 
-	// "unshadow" variables
-	if okInd {
-		namedValues[l.IndexVar] = oldValInd
-	} else {
-		delete(namedValues, l.IndexVar)
+	// Keep the blocks of the body between the header and the latch
+	latchBlock.MoveAfter(builder.GetInsertBlock())
+	exitBlock.MoveAfter(latchBlock)
+
+	builder.SetInsertPointAtEnd(latchBlock)
+	if l.forIn {
+		next()
+		ind := builder.CreateLoad(indPtr, "ind")
+		builder.CreateStore(builder.CreateAdd(ind, llvm.ConstInt(ctx.Int32Type(), 1, false), "nextind"), indPtr)
 	}
+	builder.CreateBr(headerBlock)
 
-	if okElem {
-		namedValues[l.ElementVar] = oldValElem
-	} else {
-		delete(namedValues, l.ElementVar)
+	builder.SetInsertPointAtEnd(exitBlock)
+	return llvm.ConstNull(ctx.Int1Type())
+}
+
+func (r *RangeAST) codegen() llvm.Value {
+	panic("Ranges can only be iterated by loops")
+}
+
+func (b *BranchAST) codegen() llvm.Value {
+	for i := len(loops) - 1; i >= 0; i-- {
+		if b.Label != "" && loops[i].label != b.Label {
+			continue
+		}
+
+		if b.Kind() == astContinue {
+			return builder.CreateBr(loops[i].continueBlock)
+		}
+		return builder.CreateBr(loops[i].breakBlock)
 	}
 
-	return llvm.ConstNull(llvm.Int1Type())
+	panic(fmt.Sprintf(`There is no loop labelled "%s"`, b.Label))
 }
 
 func (b *BoolAST) codegen() llvm.Value {
-	return llvm.ConstInt(llvm.Int1Type(), uint64(b.Value), false)
+	return llvm.ConstInt(ctx.Int1Type(), uint64(b.Value), false)
 }
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityNote
+)
+
+var severities = map[Severity]string{
+	SeverityError:   "error",
+	SeverityWarning: "warning",
+	SeverityNote:    "note",
+}
+
+func (s Severity) String() string {
+	return severities[s]
+}
+
+// Error codes. Syntax errors are E00xx, the rest is grouped by the pass that
+// reports it. Warnings are Wxxxx.
+const (
+	ErrUnexpectedToken = "E0001"
+	ErrUnclosed        = "E0002"
+	ErrUnknownType     = "E0003"
+	ErrUnknownVariable = "E0004"
+	ErrRedefinition    = "E0005"
+	ErrOperatorDef     = "E0006"
+	ErrAttribute       = "E0007"
+	ErrTypeMismatch    = "E0101"
+	ErrArgCount        = "E0102"
+	ErrUnknownFunction = "E0103"
+	ErrInvalidOperator = "E0104"
+	ErrReturn          = "E0105"
+	ErrImmutableAssign = "E0106"
+	ErrLoopControl     = "E0107"
+	ErrInvalidRange    = "E0108"
+	ErrStructField     = "E0109"
+	ErrRecursiveStruct = "E0110"
+	ErrEnumVariant     = "E0111"
+	ErrMatch           = "E0112"
+	ErrOverload        = "E0113"
+	ErrCodegen         = "E0900"
+	ErrInput           = "E0901"
+	WarnUnreachable    = "W0001"
+)
+
+// Diagnostic is a message about the source code. End is the position right
+// after the reported span. A diagnostic without a line refers to no place in
+// particular.
+type Diagnostic struct {
+	Severity Severity
+	Code     string
+	Start    Pos
+	End      Pos
+	Message  string
+	Notes    []string
+}
+
+// SourceFile is an input file, kept around to quote lines in diagnostics.
+type SourceFile struct {
+	Name string
+	Text string
+}
+
+func hasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func (d Diagnostic) header() string {
+	kind := d.Severity.String()
+	if d.Code != "" {
+		kind += "[" + d.Code + "]"
+	}
+
+	switch {
+	case d.Start.row > 0:
+		return fmt.Sprintf("%s:%d:%d: %s: %s", d.Start.file, d.Start.row, d.Start.col, kind, d.Message)
+	case d.Start.file != "":
+		return fmt.Sprintf("%s: %s: %s", d.Start.file, kind, d.Message)
+	default:
+		return fmt.Sprintf("%s: %s", kind, d.Message)
+	}
+}
+
+// snippet quotes the line the diagnostic starts on and underlines the span,
+// e.g. "^~~~". Spans running over several lines are underlined to the end of
+// the first one.
+func (d Diagnostic) snippet(source string) string {
+	lines := strings.Split(source, "\n")
+	if d.Start.row <= 0 || d.Start.row > len(lines) {
+		return ""
+	}
+
+	line := []rune(strings.TrimRight(lines[d.Start.row-1], "\r"))
+	start := d.Start.col - 1
+	if start < 0 {
+		start = 0
+	}
+	if start > len(line) {
+		start = len(line)
+	}
+
+	end := len(line)
+	if d.End.row == d.Start.row && d.End.col-1 <= len(line) {
+		end = d.End.col - 1
+	}
+	if end <= start {
+		end = start + 1
+	}
+
+	// Keep tabs so the underline lines up with the quoted source
+	padding := make([]rune, 0, start)
+	for _, ch := range line[:start] {
+		if ch == '\t' {
+			padding = append(padding, '\t')
+		} else {
+			padding = append(padding, ' ')
+		}
+	}
+
+	number := fmt.Sprint(d.Start.row)
+	gutter := strings.Repeat(" ", len(number))
+	return fmt.Sprintf("%s |\n%s | %s\n%s | %s^%s\n",
+		gutter,
+		number, string(line),
+		gutter, string(padding), strings.Repeat("~", end-start-1))
+}
+
+// Render formats the diagnostic the way it is printed to the terminal.
+func (d Diagnostic) Render(source string) string {
+	var b strings.Builder
+	b.WriteString(d.header())
+	b.WriteString("\n")
+	b.WriteString(d.snippet(source))
+	// Notes line up with the gutter of the quoted line
+	gutter := strings.Repeat(" ", len(fmt.Sprint(d.Start.row)))
+	for _, note := range d.Notes {
+		b.WriteString(gutter + " = note: ")
+		b.WriteString(note)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+type jsonPos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type jsonDiagnostic struct {
+	Severity string   `json:"severity"`
+	Code     string   `json:"code,omitempty"`
+	File     string   `json:"file,omitempty"`
+	Start    *jsonPos `json:"start,omitempty"`
+	End      *jsonPos `json:"end,omitempty"`
+	Message  string   `json:"message"`
+	Notes    []string `json:"notes,omitempty"`
+}
+
+// writeDiagnostics prints the diagnostics either for humans ("human") or as
+// one JSON object per line ("json").
+func writeDiagnostics(w io.Writer, diags []Diagnostic, files []SourceFile, format string) error {
+	sources := make(map[string]string, len(files))
+	for _, file := range files {
+		sources[file.Name] = file.Text
+	}
+
+	encoder := json.NewEncoder(w)
+	for _, d := range diags {
+		if format != "json" {
+			if _, err := io.WriteString(w, d.Render(sources[d.Start.file])); err != nil {
+				return err
+			}
+			continue
+		}
+
+		out := jsonDiagnostic{
+			Severity: d.Severity.String(),
+			Code:     d.Code,
+			File:     d.Start.file,
+			Message:  d.Message,
+			Notes:    d.Notes,
+		}
+		if d.Start.row > 0 {
+			out.Start = &jsonPos{d.Start.row, d.Start.col}
+			out.End = &jsonPos{d.End.row, d.End.col}
+		}
+		if err := encoder.Encode(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const usage = `Usage: novum <command> [flags] <files...>
+
+Commands:
+  build    compile the input files into an executable
+  run      compile the input files and run the result
+  check    parse and type check the input files without generating code
+  emit     write the output selected by --emit (default: ir)
+
+Flags:
+  -o <file>                      output path ("-" writes ir/asm to stdout)
+  --emit=ir|bc|asm|obj|exe       kind of output to produce
+  -O0, -O1, -O2, -O3             optimization level (default: -O2)
+  --target=<triple>              target triple (default: host)
+  --error-format=human|json      how diagnostics are printed (default: human)
+  --interp                       'run' only: evaluate the program without LLVM
+`
+
+const (
+	EmitIR  = "ir"
+	EmitBC  = "bc"
+	EmitASM = "asm"
+	EmitObj = "obj"
+	EmitExe = "exe"
+)
+
+var emitExtensions = map[string]string{
+	EmitIR:  ".ll",
+	EmitBC:  ".bc",
+	EmitASM: ".s",
+	EmitObj: ".o",
+	EmitExe: "",
+}
+
+type Options struct {
+	Command     string
+	Files       []string
+	Output      string
+	Emit        string
+	OptLevel    int
+	Target      string
+	ErrorFormat string
+	Interp      bool
+	Args        []string
+}
+
+func parseOptions(args []string) (Options, error) {
+	opts := Options{OptLevel: 2, ErrorFormat: "human"}
+	if len(args) == 0 {
+		return opts, errors.New("no command given")
+	}
+
+	opts.Command = args[0]
+	switch opts.Command {
+	case "build", "run":
+		opts.Emit = EmitExe
+	case "emit":
+		opts.Emit = EmitIR
+	case "check":
+	default:
+		return opts, fmt.Errorf("unknown command '%s'", opts.Command)
+	}
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		// Everything after "--" is passed to the program started by "run"
+		if arg == "--" {
+			opts.Args = args[i+1:]
+			break
+		}
+
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			opts.Files = append(opts.Files, arg)
+			continue
+		}
+
+		name, value, hasValue := arg, "", false
+		if eq := strings.IndexByte(arg, '='); eq >= 0 {
+			name, value, hasValue = arg[:eq], arg[eq+1:], true
+		}
+
+		needValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("flag '%s' needs a value", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		var err error
+		switch name {
+		case "-o":
+			opts.Output, err = needValue()
+		case "--emit", "-emit":
+			opts.Emit, err = needValue()
+			if _, ok := emitExtensions[opts.Emit]; err == nil && !ok {
+				err = fmt.Errorf("unknown output kind '%s'", opts.Emit)
+			}
+		case "--target", "-target":
+			opts.Target, err = needValue()
+		case "--error-format", "-error-format":
+			opts.ErrorFormat, err = needValue()
+			if err == nil && opts.ErrorFormat != "human" && opts.ErrorFormat != "json" {
+				err = fmt.Errorf("unknown error format '%s'", opts.ErrorFormat)
+			}
+		case "--interp", "-interp":
+			opts.Interp = true
+		case "-O0", "-O1", "-O2", "-O3":
+			opts.OptLevel, _ = strconv.Atoi(name[2:])
+		case "-h", "--help":
+			return opts, errors.New("help requested")
+		default:
+			err = fmt.Errorf("unknown flag '%s'", name)
+		}
+
+		if err != nil {
+			return opts, err
+		}
+	}
+
+	if len(opts.Files) == 0 {
+		return opts, errors.New("no input files")
+	}
+
+	if opts.Command == "run" && opts.Emit != EmitExe {
+		return opts, errors.New("'run' can only produce an executable")
+	}
+
+	if opts.Interp && opts.Command != "run" {
+		return opts, errors.New("'--interp' can only be used with 'run'")
+	}
+
+	if opts.Output == "" && (opts.Command == "build" || opts.Command == "emit") {
+		base := strings.TrimSuffix(filepath.Base(opts.Files[0]), filepath.Ext(opts.Files[0]))
+		opts.Output = base + emitExtensions[opts.Emit]
+		if opts.Emit == EmitExe && base+emitExtensions[opts.Emit] == opts.Files[0] {
+			opts.Output = "a.out"
+		}
+	}
+
+	return opts, nil
+}
+
+func readSources(files []string) ([]SourceFile, []Diagnostic) {
+	var sources []SourceFile
+	var diags []Diagnostic
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			diags = append(diags, Diagnostic{
+				Severity: SeverityError,
+				Code:     ErrInput,
+				Message:  err.Error(),
+			})
+			continue
+		}
+		sources = append(sources, SourceFile{file, string(data)})
+	}
+	return sources, diags
+}
+
+// compile parses and checks every input file, then generates the module
+// unless only checking or interpreting was asked for. Code generation still
+// reports internal errors by panicking, so they are recovered here and turned
+// into diagnostics.
+func compile(opts Options, sources []SourceFile) (decls []AST, diags []Diagnostic) {
+	defer func() {
+		if r := recover(); r != nil {
+			diags = append(diags, Diagnostic{
+				Severity: SeverityError,
+				Code:     ErrCodegen,
+				Message:  fmt.Sprint(r),
+			})
+		}
+	}()
+
+	decls, diags = parseSources(sources)
+	if hasErrors(diags) {
+		return decls, diags
+	}
+
+	diags = append(diags, checkModule(decls)...)
+	if hasErrors(diags) || opts.Command == "check" || opts.Interp {
+		return decls, diags
+	}
+
+	return decls, append(diags, generateNative(opts, decls)...)
+}
+
+func runProgram(path string, args []string) (int, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return 1, err
+	}
+
+	cmd := exec.Command(abs, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
+
+func fail(format string, args ...interface{}) int {
+	fmt.Fprintf(os.Stderr, "novum: error: "+format+"\n", args...)
+	return 1
+}
+
+func runDriver(args []string) int {
+	opts, err := parseOptions(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "novum: %s\n\n%s", err.Error(), usage)
+		return 2
+	}
+
+	sources, diags := readSources(opts.Files)
+	var decls []AST
+	if len(diags) == 0 {
+		decls, diags = compile(opts, sources)
+	}
+
+	if err := writeDiagnostics(os.Stderr, diags, sources, opts.ErrorFormat); err != nil {
+		return fail("%s", err.Error())
+	}
+
+	if hasErrors(diags) {
+		return 1
+	}
+
+	if opts.Command == "check" {
+		return 0
+	}
+
+	if opts.Interp {
+		code, err := interpret(decls, os.Stdout)
+		if err != nil {
+			return fail("%s", err.Error())
+		}
+		return code
+	}
+
+	if opts.Command == "run" {
+		tmpDir, err := ioutil.TempDir("", "novum-run")
+		if err != nil {
+			return fail("%s", err.Error())
+		}
+		defer os.RemoveAll(tmpDir)
+
+		if opts.Output == "" {
+			opts.Output = filepath.Join(tmpDir, "a.out")
+		}
+	}
+
+	if err := writeOutput(opts); err != nil {
+		return fail("%s", err.Error())
+	}
+
+	if opts.Command == "run" {
+		code, err := runProgram(opts.Output, opts.Args)
+		if err != nil {
+			return fail("%s", err.Error())
+		}
+		return code
+	}
+
+	return 0
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dumpTokens lists the tokens of the source, one per line, with the position
+// they start at.
+func dumpTokens(file, source string) string {
+	var b strings.Builder
+	lexer := NewLexer(file, source)
+	for {
+		fmt.Fprintf(&b, "%d:%d %s", lexer.tokStart.row, lexer.tokStart.col, tokens[lexer.token])
+		switch lexer.token {
+		case TokIdentifier, TokAtom:
+			fmt.Fprintf(&b, " %s", lexer.identifier)
+		case TokNumber:
+			fmt.Fprintf(&b, " %v", lexer.numVal)
+		case TokStr, TokChar:
+			fmt.Fprintf(&b, " %q", lexer.strVal)
+		case TokUnknown:
+			fmt.Fprintf(&b, " %q", lexer.unknownVal)
+		}
+		b.WriteString("\n")
+
+		if lexer.token == TokEOF {
+			return b.String()
+		}
+		lexer.nextToken()
+	}
+}
+
+// astDumper prints an indented tree of declarations with the types the
+// checker annotated.
+type astDumper struct {
+	b     strings.Builder
+	depth int
+}
+
+func dumpAST(decls []AST) string {
+	var d astDumper
+	for _, decl := range decls {
+		d.node(decl)
+	}
+	return d.b.String()
+}
+
+func (d *astDumper) line(format string, args ...interface{}) {
+	d.b.WriteString(strings.Repeat("  ", d.depth))
+	fmt.Fprintf(&d.b, format, args...)
+	d.b.WriteString("\n")
+}
+
+func (d *astDumper) children(nodes ...AST) {
+	d.depth++
+	for _, node := range nodes {
+		if node != nil {
+			d.node(node)
+		}
+	}
+	d.depth--
+}
+
+func (d *astDumper) block(label string, b *BlockAST) {
+	d.line("%s", label)
+	d.children(b.Elements...)
+}
+
+func typed(ast AST) string {
+	if typ := typeOf(ast); typ != "" {
+		return ": " + typ
+	}
+	return ": ?"
+}
+
+func via(fn string) string {
+	if fn != "" {
+		return " via " + fn
+	}
+	return ""
+}
+
+func prototype(p *PrototypeAST) string {
+	args := make([]string, 0, len(p.Args))
+	for _, arg := range p.Args {
+		args = append(args, arg.Name+": "+arg.ArgType)
+	}
+
+	// A return type inferred from a broken body stays unknown
+	returnType := p.ReturnType
+	if returnType == "" {
+		returnType = "?"
+	}
+	s := fmt.Sprintf("%s(%s): %s", p.Name, strings.Join(args, ", "), returnType)
+	if p.IsOperator && p.IsBinaryOp {
+		s += fmt.Sprintf(" [binary, precedence %d]", p.Precedence)
+	} else if p.IsOperator {
+		s += " [unary]"
+	}
+	return s
+}
+
+func pattern(arm *MatchArm) string {
+	switch {
+	case arm.wildcard():
+		return "_"
+	case arm.Variant == "":
+		return literalPattern(arm.Literal)
+	case len(arm.Bindings) == 0:
+		return arm.Enum + "." + arm.Variant
+	}
+
+	bindings := make([]string, 0, len(arm.Bindings))
+	for _, b := range arm.Bindings {
+		if b.Name == "_" {
+			bindings = append(bindings, "_")
+		} else {
+			bindings = append(bindings, b.Name+typed(b))
+		}
+	}
+	return fmt.Sprintf("%s.%s(%s)", arm.Enum, arm.Variant, strings.Join(bindings, ", "))
+}
+
+func (d *astDumper) node(ast AST) {
+	switch n := ast.(type) {
+	case *FunctionAST:
+		d.line("Function %s", prototype(&n.Proto))
+		d.children(n.Body.Elements...)
+	case *PrototypeAST:
+		d.line("Extern %s", prototype(n))
+	case *StructAST:
+		d.line("Struct %s", n.Name)
+		d.depth++
+		for _, field := range n.Fields {
+			d.line("%s: %s", field.Name, field.ArgType)
+		}
+		d.depth--
+	case *EnumAST:
+		d.line("Enum %s", n.Name)
+		d.depth++
+		for _, variant := range n.Variants {
+			if len(variant.Payload) == 0 {
+				d.line("%s", variant.Name)
+			} else {
+				d.line("%s(%s)", variant.Name, strings.Join(variant.Payload, ", "))
+			}
+		}
+		d.depth--
+	case *VariantAST:
+		d.line("Variant %s.%s%s", n.Enum, n.Variant, typed(n))
+		d.children(n.Args...)
+	case *MatchAST:
+		d.line("Match%s", typed(n))
+		d.children(n.Value)
+		d.depth++
+		for i := range n.Arms {
+			arm := &n.Arms[i]
+			d.line("Case %s", pattern(arm))
+			if arm.Block != nil {
+				d.depth++
+				d.block("Block", arm.Block)
+				d.depth--
+			} else {
+				d.children(arm.Value)
+			}
+		}
+		d.depth--
+	case *IfElseAST:
+		d.line("If")
+		d.children(n.Condition)
+		d.depth++
+		d.block("Then", &n.TrueBody)
+		for i := range n.ElseIfBody {
+			d.line("ElseIf")
+			d.children(n.ElseIfBody[i].Condition)
+			d.block("Then", &n.ElseIfBody[i].Body)
+		}
+		if len(n.ElseBody.Elements) != 0 {
+			d.block("Else", &n.ElseBody)
+		}
+		d.depth--
+	case *LoopAST:
+		label := ""
+		if n.Label != "" {
+			label = " [label " + n.Label + "]"
+		}
+		if n.forIn && n.IndexVar == "" {
+			d.line("ForIn %s%s", n.ElementVar, label)
+		} else if n.forIn {
+			d.line("ForIn %s, %s%s", n.IndexVar, n.ElementVar, label)
+		} else {
+			d.line("Loop%s", label)
+		}
+		d.children(n.Condition)
+		d.depth++
+		d.block("Body", &n.Body)
+		d.depth--
+	case *ReturnAST:
+		d.line("Return")
+		d.children(n.Body)
+	case *VarDeclAST:
+		keyword := "Let"
+		if n.Mutable {
+			keyword = "Var"
+		}
+		d.line("%s %s: %s", keyword, n.Name, n.VarType)
+		d.children(n.Value)
+	case *AssignAST:
+		if v, ok := n.Target.(*VariableAST); ok {
+			d.line("Assign %s", v.Name)
+			d.children(n.Value)
+		} else {
+			d.line("Assign")
+			d.children(n.Target, n.Value)
+		}
+	case *BranchAST:
+		keyword := "Break"
+		if n.Kind() == astContinue {
+			keyword = "Continue"
+		}
+		if n.Label != "" {
+			keyword += " " + n.Label
+		}
+		d.line("%s", keyword)
+	case *RangeAST:
+		op := ".."
+		if n.Inclusive {
+			op = "..="
+		}
+		d.line("Range %s", op)
+		d.children(n.Start, n.End, n.Step)
+	case *NumberLiteralAST:
+		d.line("Number %s%s", numberText(n.Value), typed(n))
+	case *StringAST:
+		d.line("String %q%s", n.Value, typed(n))
+	case *CharAST:
+		d.line("Char %q%s", n.Value, typed(n))
+	case *CastAST:
+		if n.Implicit {
+			d.line("Cast implicit%s", typed(n))
+		} else {
+			d.line("Cast%s", typed(n))
+		}
+		d.children(n.Value)
+	case *AtomAST:
+		d.line("Atom :%s%s", n.Value, typed(n))
+	case *BoolAST:
+		d.line("Bool %v%s", n.Value != 0, typed(n))
+	case *VariableAST:
+		d.line("Variable %s%s", n.Name, typed(n))
+	case *CallAST:
+		d.line("Call %s%s", n.Callee, typed(n))
+		d.children(n.args...)
+	case *BinaryAST:
+		d.line("Binary %s%s%s", n.Op, typed(n), via(n.Func))
+		d.children(n.Lhs, n.Rhs)
+	case *ArrayAST:
+		d.line("Array%s", typed(n))
+		d.children(n.Elements...)
+	case *IndexAST:
+		d.line("Index%s", typed(n))
+		d.children(n.Array, n.Index)
+	case *StructLitAST:
+		d.line("StructLit %s%s", n.Name, typed(n))
+		d.depth++
+		for _, field := range n.Fields {
+			d.line("%s", field.Name)
+			d.children(field.Value)
+		}
+		d.depth--
+	case *FieldAST:
+		d.line("Field %s%s", n.Name, typed(n))
+		d.children(n.Struct)
+	case *UnaryAST:
+		d.line("Unary %c%s%s", rune(n.Operator), typed(n), via(n.Func))
+		d.children(n.Operand)
+	default:
+		d.line("%T", ast)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import "fmt"
+
+// checkFlow follows the control flow of a checked function. It warns about
+// statements that can never run, since one before them in their block always
+// leaves it, and reports a function returning a value whose body can end
+// without returning one.
+func (c *Checker) checkFlow(f *FunctionAST) {
+	if c.leaves(&f.Body) || f.Proto.ReturnType == LitVoid {
+		return
+	}
+
+	name := sourceName(&f.Proto)
+	msg := fmt.Sprintf(`Missing return at the end of function "%s".`, name)
+	if f.Proto.Receiver != "" {
+		msg = fmt.Sprintf(`Missing return at the end of method "%s" of %s.`, name, f.Proto.Receiver)
+	}
+	c.errorAt(f.Proto.Pos, len(name), ErrReturn, msg,
+		fmt.Sprintf("it returns %s, but its body can end without a return", f.Proto.ReturnType))
+}
+
+// leaves tells whether running the block never gets past its end: it returns,
+// breaks or continues a loop, or loops forever.
+func (c *Checker) leaves(b *BlockAST) bool {
+	for i, stmt := range b.Elements {
+		if !c.leavesStmt(stmt) {
+			continue
+		}
+
+		if i+1 < len(b.Elements) {
+			next := b.Elements[i+1]
+			if ret, ok := next.(*ReturnAST); !ok || !ret.Implicit {
+				pos := stmt.Position()
+				c.warningAt(next.Position(), 1, WarnUnreachable, "Unreachable code.",
+					fmt.Sprintf("nothing runs after the %s at %d:%d", statementName(stmt), pos.row, pos.col))
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (c *Checker) leavesStmt(stmt AST) bool {
+	switch s := stmt.(type) {
+	case *ReturnAST, *BranchAST:
+		return true
+	case *IfElseAST:
+		// Every branch is followed, for the warnings in each
+		all := c.leaves(&s.TrueBody)
+		for i := range s.ElseIfBody {
+			all = c.leaves(&s.ElseIfBody[i].Body) && all
+		}
+		return c.leaves(&s.ElseBody) && all
+	case *LoopAST:
+		c.leaves(&s.Body)
+		cond, ok := s.Condition.(*BoolAST)
+		return !s.forIn && ok && cond.Value != 0 && !breaks(s, s.Body.Elements, false)
+	case *MatchAST:
+		all := true
+		for _, arm := range s.Arms {
+			all = arm.Block != nil && c.leaves(arm.Block) && all
+		}
+		return all
+	}
+	return false
+}
+
+// breaks tells whether one of the statements breaks out of the loop l. nested
+// tells whether they are in another loop inside l, which unlabelled breaks
+// leave instead.
+func breaks(l *LoopAST, stmts []AST, nested bool) bool {
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *BranchAST:
+			if s.Kind() == astBreak && (s.Label == "" && !nested || s.Label != "" && s.Label == l.Label) {
+				return true
+			}
+		case *IfElseAST:
+			if breaks(l, s.TrueBody.Elements, nested) || breaks(l, s.ElseBody.Elements, nested) {
+				return true
+			}
+			for _, elseIf := range s.ElseIfBody {
+				if breaks(l, elseIf.Body.Elements, nested) {
+					return true
+				}
+			}
+		case *LoopAST:
+			if breaks(l, s.Body.Elements, true) {
+				return true
+			}
+		case *MatchAST:
+			for _, arm := range s.Arms {
+				if arm.Block != nil && breaks(l, arm.Block.Elements, nested) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// statementName names a statement that can leave its block in notes.
+func statementName(stmt AST) string {
+	switch s := stmt.(type) {
+	case *ReturnAST:
+		return "return"
+	case *BranchAST:
+		if s.Kind() == astContinue {
+			return "continue"
+		}
+		return "break"
+	case *IfElseAST:
+		return "if"
+	case *LoopAST:
+		return "loop"
+	default:
+		return "match"
+	}
+}
@@ -0,0 +1,17 @@
+//go:build llvm
+// +build llvm
+
+package main
+
+func init() {
+	generateIR = func(decls []AST) string {
+		// The IR is for one target whatever the host, since the C calling
+		// convention of externs depends on it
+		InitModuleAndPassManager(0)
+		if err := initTarget("x86_64-unknown-linux-gnu", 0); err != nil {
+			panic(err)
+		}
+		generateModule(decls)
+		return module.String()
+	}
+}
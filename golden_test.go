@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// generateIR returns the IR of checked declarations. It is only set when the
+// tests are built with the llvm tag.
+var generateIR func(decls []AST) string
+
+// TestGolden runs every testdata/*.nv case through the compiler and compares
+// each stage with the golden file next to it:
+//
+//	.tokens  token stream
+//	.ast     checked AST
+//	.diag    diagnostics
+//	.out     output of the interpreted program and its exit status
+//	.ll      LLVM IR (only with -tags llvm)
+//
+// A missing golden file stands for empty output, so with -tags llvm a case
+// that compiles fails until its .ll file is generated.
+func TestGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.nv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		file := file
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".nv"), func(t *testing.T) {
+			runGolden(t, file)
+		})
+	}
+}
+
+func runGolden(t *testing.T, file string) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := strings.TrimSuffix(file, ".nv")
+	sources := []SourceFile{{filepath.Base(file), string(data)}}
+
+	checkGolden(t, base+".tokens", dumpTokens(sources[0].Name, sources[0].Text))
+
+	decls, diags := compile(Options{Command: "check"}, sources)
+	checkGolden(t, base+".ast", dumpAST(decls))
+
+	var diagOut bytes.Buffer
+	if err := writeDiagnostics(&diagOut, diags, sources, "human"); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, base+".diag", diagOut.String())
+
+	// Programs with errors leave the remaining golden files empty
+	var out bytes.Buffer
+	ir := ""
+	if !hasErrors(diags) {
+		code, err := interpret(decls, &out)
+		if err != nil {
+			fmt.Fprintf(&out, "error: %v\n", err)
+		}
+		if code != 0 {
+			fmt.Fprintf(&out, "exit status %d\n", code)
+		}
+
+		if generateIR != nil {
+			ir = generateIR(decls)
+		}
+	}
+
+	checkGolden(t, base+".out", out.String())
+	if generateIR != nil {
+		checkGolden(t, base+".ll", ir)
+	}
+}
+
+func checkGolden(t *testing.T, path, got string) {
+	t.Helper()
+
+	if *update {
+		if got == "" {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			return
+		}
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+
+	if string(want) != got {
+		t.Errorf("%s is out of date (run go test -update):\n--- want\n%s--- got\n%s", path, want, got)
+	}
+}
@@ -0,0 +1,692 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// flow tells the caller of a statement how control leaves it.
+type flow int
+
+const (
+	flowNext flow = iota
+	flowReturn
+	flowBreak
+	flowContinue
+)
+
+// structValue holds the fields of a struct in their order.
+type structValue []interface{}
+
+// enumValue is a variant of an enum, told by its tag, and its payload.
+type enumValue struct {
+	tag     int
+	payload []interface{}
+}
+
+// runtimeError stops the interpreted program.
+type runtimeError struct {
+	Pos
+	msg string
+}
+
+func (e runtimeError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: runtime error: %s", e.file, e.row, e.col, e.msg)
+}
+
+// exitProgram unwinds the interpreter when the program calls exit.
+type exitProgram struct {
+	code int
+}
+
+// Interpreter evaluates checked declarations directly, without generating
+// any code. Values are Go values of the matching type: int32 for int, float64
+// for float, bool for bool, string for str and atom (its name), and byte for
+// char. Arrays are []interface{}, sharing their elements between copies like
+// compiled arrays. Structs are structValue, which is never changed in place
+// so that copies stay independent, and enums are enumValue.
+type Interpreter struct {
+	functions map[string]*FunctionAST
+	externs   map[string]*PrototypeAST
+	scopes    []map[string]interface{}
+	ret       interface{}
+	label     string // of the loop a break or continue leaves
+	out       io.Writer
+}
+
+// interpret runs the main function of the checked declarations and returns
+// the exit code of the program.
+func interpret(decls []AST, out io.Writer) (code int, err error) {
+	in := Interpreter{
+		functions: map[string]*FunctionAST{},
+		externs:   map[string]*PrototypeAST{},
+		out:       out,
+	}
+
+	for _, decl := range decls {
+		switch d := decl.(type) {
+		case *FunctionAST:
+			in.functions[d.Proto.Name] = d
+		case *PrototypeAST:
+			in.externs[d.Name] = d
+		}
+	}
+
+	main, ok := in.functions["main"]
+	if !ok {
+		return 1, errors.New("there is no main function to run")
+	}
+
+	defer func() {
+		switch r := recover().(type) {
+		case nil:
+		case exitProgram:
+			code = r.code
+		case runtimeError:
+			code, err = 1, r
+		default:
+			panic(r)
+		}
+	}()
+
+	if result, ok := in.call(main, nil).(int32); ok {
+		return int(result), nil
+	}
+	return 0, nil
+}
+
+func (in *Interpreter) pushScope() {
+	in.scopes = append(in.scopes, map[string]interface{}{})
+}
+
+func (in *Interpreter) popScope() {
+	in.scopes = in.scopes[:len(in.scopes)-1]
+}
+
+func (in *Interpreter) define(name string, val interface{}) {
+	in.scopes[len(in.scopes)-1][name] = val
+}
+
+func (in *Interpreter) lookup(name string) map[string]interface{} {
+	for i := len(in.scopes) - 1; i >= 0; i-- {
+		if _, ok := in.scopes[i][name]; ok {
+			return in.scopes[i]
+		}
+	}
+	panic(fmt.Sprintf(`Variable "%s" does not exist!`, name))
+}
+
+func (in *Interpreter) call(fn *FunctionAST, args []interface{}) interface{} {
+	outer := in.scopes
+	defer func() { in.scopes = outer }()
+
+	in.scopes = nil
+	in.pushScope()
+	for i, arg := range fn.Proto.Args {
+		in.define(arg.Name, args[i])
+	}
+
+	in.ret = nil
+	in.execBlock(&fn.Body)
+	return in.ret
+}
+
+func (in *Interpreter) callFunction(name string, args []interface{}, pos Pos) interface{} {
+	if fn, ok := in.functions[name]; ok {
+		return in.call(fn, args)
+	}
+
+	builtin, ok := builtins[name]
+	if !ok {
+		panic(runtimeError{pos, fmt.Sprintf(`extern function "%s" is not available in the interpreter`, name)})
+	}
+
+	// Builtins return C's int, whatever number type the extern declares
+	result := builtin(in, args)
+	if proto, ok := in.externs[name]; ok && result != nil {
+		if _, ok := castType(proto.ReturnType); ok {
+			result = castValue(result, proto.ReturnType)
+		}
+	}
+	return result
+}
+
+func (in *Interpreter) execBlock(b *BlockAST) flow {
+	in.pushScope()
+	defer in.popScope()
+
+	for _, stmt := range b.Elements {
+		if f := in.execStmt(stmt); f != flowNext {
+			return f
+		}
+	}
+	return flowNext
+}
+
+func (in *Interpreter) execStmt(stmt AST) flow {
+	switch s := stmt.(type) {
+	case *IfElseAST:
+		if in.eval(s.Condition).(bool) {
+			return in.execBlock(&s.TrueBody)
+		}
+		for i := range s.ElseIfBody {
+			if in.eval(s.ElseIfBody[i].Condition).(bool) {
+				return in.execBlock(&s.ElseIfBody[i].Body)
+			}
+		}
+		return in.execBlock(&s.ElseBody)
+	case *LoopAST:
+		return in.execLoop(s)
+	case *ReturnAST:
+		in.ret = nil
+		if s.Body != nil {
+			in.ret = in.eval(s.Body)
+		}
+		return flowReturn
+	case *VarDeclAST:
+		in.define(s.Name, in.eval(s.Value))
+	case *AssignAST:
+		val := in.eval(s.Value)
+		_, set := in.ref(s.Target)
+		set(val)
+	case *BranchAST:
+		in.label = s.Label
+		if s.Kind() == astContinue {
+			return flowContinue
+		}
+		return flowBreak
+	case *MatchAST:
+		in.pushScope()
+		defer in.popScope()
+		arm := in.matchArm(s, in.eval(s.Value))
+		if arm.Block != nil {
+			return in.execBlock(arm.Block)
+		}
+		in.eval(arm.Value)
+	default:
+		in.eval(stmt)
+	}
+	return flowNext
+}
+
+// ref evaluates the place of a variable, an array element or a struct field
+// once and returns functions to read and write it. A field is written to a
+// copy of the struct, which is stored where the struct came from.
+func (in *Interpreter) ref(target AST) (get func() interface{}, set func(interface{})) {
+	switch target := target.(type) {
+	case *VariableAST:
+		scope := in.lookup(target.Name)
+		return func() interface{} { return scope[target.Name] },
+			func(val interface{}) { scope[target.Name] = val }
+	case *IndexAST:
+		array, i := in.evalIndex(target)
+		return func() interface{} { return array[i] },
+			func(val interface{}) { array[i] = val }
+	case *FieldAST:
+		getStruct, setStruct := in.ref(target.Struct)
+		return func() interface{} { return getStruct().(structValue)[target.Index] },
+			func(val interface{}) {
+				s := append(structValue(nil), getStruct().(structValue)...)
+				s[target.Index] = val
+				setStruct(s)
+			}
+	}
+	panic(fmt.Sprintf("can't assign to %T", target))
+}
+
+func (in *Interpreter) execLoop(l *LoopAST) flow {
+	if !l.forIn {
+		for in.eval(l.Condition).(bool) {
+			if f, done := in.iterationEnd(l, in.execBlock(&l.Body)); done {
+				return f
+			}
+		}
+		return flowNext
+	}
+
+	if r, ok := l.Condition.(*RangeAST); ok {
+		return in.execRange(l, r)
+	}
+
+	if array, ok := in.eval(l.Condition).([]interface{}); ok {
+		for i := 0; i < len(array); i++ {
+			if f, done := in.iteration(l, int32(i), array[i]); done {
+				return f
+			}
+		}
+		return flowNext
+	}
+
+	str := in.eval(l.Condition).(string)
+	for i := 0; i < len(str); i++ {
+		if f, done := in.iteration(l, int32(i), str[i]); done {
+			return f
+		}
+	}
+	return flowNext
+}
+
+func (in *Interpreter) execRange(l *LoopAST, r *RangeAST) flow {
+	val, end, step := in.eval(r.Start).(int32), in.eval(r.End).(int32), int32(1)
+	if r.Step != nil {
+		step = in.eval(r.Step).(int32)
+	}
+	if step == 0 {
+		in.runtimePanic("step of the range is equal to 0")
+	}
+
+	for i := int32(0); ; i++ {
+		if step > 0 && (val > end || val == end && !r.Inclusive) ||
+			step < 0 && (val < end || val == end && !r.Inclusive) {
+			return flowNext
+		}
+		if f, done := in.iteration(l, i, val); done {
+			return f
+		}
+		val += step
+	}
+}
+
+// iteration runs the body of a for-in loop once.
+func (in *Interpreter) iteration(l *LoopAST, index int32, element interface{}) (flow, bool) {
+	in.pushScope()
+	if l.IndexVar != "" {
+		in.define(l.IndexVar, index)
+	}
+	in.define(l.ElementVar, element)
+	f := in.execBlock(&l.Body)
+	in.popScope()
+
+	return in.iterationEnd(l, f)
+}
+
+// iterationEnd tells whether the loop l is done after an iteration ended
+// with f, and how control leaves it then. A break or continue without a
+// label, or with the label of l, is for l. Others are passed on to the outer
+// loops.
+func (in *Interpreter) iterationEnd(l *LoopAST, f flow) (flow, bool) {
+	switch {
+	case f == flowNext:
+		return flowNext, false
+	case (f == flowBreak || f == flowContinue) && (in.label == "" || in.label == l.Label):
+		return flowNext, f == flowBreak
+	default:
+		return f, true
+	}
+}
+
+func (in *Interpreter) eval(expr AST) interface{} {
+	switch e := expr.(type) {
+	case *NumberLiteralAST:
+		return castValue(e.Value, e.Type())
+	case *StringAST:
+		return e.Value
+	case *CharAST:
+		return e.Value
+	case *AtomAST:
+		return e.Value
+	case *BoolAST:
+		return e.Value != 0
+	case *VariableAST:
+		return in.lookup(e.Name)[e.Name]
+	case *CallAST:
+		args := make([]interface{}, 0, len(e.args))
+		for _, arg := range e.args {
+			args = append(args, in.eval(arg))
+		}
+		return in.callFunction(e.Callee, args, e.Pos)
+	case *BinaryAST:
+		l := in.eval(e.Lhs)
+		if e.Func == "" && (e.Op == "&&" || e.Op == "||") {
+			// The right side only runs when the left one doesn't decide
+			if l.(bool) == (e.Op == "||") {
+				return l
+			}
+			return in.eval(e.Rhs)
+		}
+
+		r := in.eval(e.Rhs)
+		if e.Func != "" {
+			return in.callFunction(e.Func, []interface{}{l, r}, e.Pos)
+		}
+		return in.evalBinary(e, l, r)
+	case *ArrayAST:
+		array := make([]interface{}, 0, len(e.Elements))
+		for _, elem := range e.Elements {
+			array = append(array, in.eval(elem))
+		}
+		return array
+	case *IndexAST:
+		array, i := in.evalIndex(e)
+		return array[i]
+	case *StructLitAST:
+		s := make(structValue, len(e.Fields))
+		for _, field := range e.Fields {
+			s[field.Index] = in.eval(field.Value)
+		}
+		return s
+	case *FieldAST:
+		return in.eval(e.Struct).(structValue)[e.Index]
+	case *VariantAST:
+		payload := make([]interface{}, 0, len(e.Args))
+		for _, arg := range e.Args {
+			payload = append(payload, in.eval(arg))
+		}
+		return enumValue{e.Tag, payload}
+	case *MatchAST:
+		in.pushScope()
+		defer in.popScope()
+		return in.eval(in.matchArm(e, in.eval(e.Value)).Value)
+	case *UnaryAST:
+		operand := in.eval(e.Operand)
+		if e.Func == "" {
+			switch operand := operand.(type) {
+			case bool:
+				return !operand
+			case float64:
+				return -operand
+			case float32:
+				return -operand
+			default:
+				i, _, _ := number(operand)
+				return castValue(-int64(i), e.Type())
+			}
+		}
+		return in.callFunction(e.Func, []interface{}{operand}, e.Pos)
+	case *CastAST:
+		return castValue(in.eval(e.Value), e.To)
+	default:
+		panic(fmt.Sprintf("can't evaluate %T", expr))
+	}
+}
+
+// matchArm returns the first arm of the match whose pattern matches the
+// value, after defining the names it binds in the current scope.
+func (in *Interpreter) matchArm(m *MatchAST, value interface{}) *MatchArm {
+	for i := range m.Arms {
+		arm := &m.Arms[i]
+		switch {
+		case arm.wildcard():
+			return arm
+		case arm.Variant != "":
+			variant := value.(enumValue)
+			if variant.tag != arm.Tag {
+				continue
+			}
+			for i, b := range arm.Bindings {
+				if b.Name != "_" {
+					in.define(b.Name, variant.payload[i])
+				}
+			}
+			return arm
+		case in.eval(arm.Literal) == value:
+			return arm
+		}
+	}
+	panic("no arm of the match matches the value")
+}
+
+// evalIndex evaluates the array and the index of a[i], checking the bounds.
+func (in *Interpreter) evalIndex(e *IndexAST) ([]interface{}, int32) {
+	array := in.eval(e.Array).([]interface{})
+	i := in.eval(e.Index).(int32)
+	if i < 0 || int(i) >= len(array) {
+		in.runtimePanic(indexOutOfRange(e.Pos))
+	}
+	return array, i
+}
+
+// runtimePanic stops the program the same way the compiled runtime checks do.
+func (in *Interpreter) runtimePanic(msg string) {
+	fmt.Fprintf(in.out, "Panic: %s\n", msg)
+	panic(exitProgram{0})
+}
+
+// indexOutOfRange is the panic message for an index outside its array. The
+// compiled bounds check prints the same.
+func indexOutOfRange(pos Pos) string {
+	return fmt.Sprintf("index out of range at %s:%d:%d", pos.file, pos.row, pos.col)
+}
+
+func (in *Interpreter) divisionByZero() {
+	in.runtimePanic("right side of the equation is equal to 0")
+}
+
+// evalInteger evaluates a binary operator on integers of the type described
+// by info, wrapping around like the compiled code.
+func (in *Interpreter) evalInteger(b *BinaryAST, info numberType, l, r interface{}) interface{} {
+	x, _, _ := number(l)
+	y, _, _ := number(r)
+	count := y % uint64(info.bits)
+
+	switch b.Op {
+	case "+":
+		return castValue(x+y, b.Type())
+	case "-":
+		return castValue(x-y, b.Type())
+	case "*":
+		return castValue(x*y, b.Type())
+	case "&":
+		return castValue(x&y, b.Type())
+	case "|":
+		return castValue(x|y, b.Type())
+	case "^":
+		return castValue(x^y, b.Type())
+	case "<<":
+		return castValue(x<<count, b.Type())
+	case "==":
+		return x == y
+	case "!=":
+		return x != y
+	case "/", "%":
+		if y == 0 {
+			in.divisionByZero()
+		}
+	}
+
+	if !info.signed {
+		switch b.Op {
+		case "/":
+			return castValue(x/y, b.Type())
+		case "%":
+			return castValue(x%y, b.Type())
+		case ">>":
+			return castValue(x>>count, b.Type())
+		case "<":
+			return x < y
+		case "<=":
+			return x <= y
+		case ">":
+			return x > y
+		case ">=":
+			return x >= y
+		}
+	}
+
+	sx, sy := int64(x), int64(y)
+	switch b.Op {
+	case "/":
+		return castValue(sx/sy, b.Type())
+	case "%":
+		return castValue(sx%sy, b.Type())
+	case ">>":
+		return castValue(sx>>count, b.Type())
+	case "<":
+		return sx < sy
+	case "<=":
+		return sx <= sy
+	case ">":
+		return sx > sy
+	case ">=":
+		return sx >= sy
+	}
+	panic(runtimeError{b.Pos, fmt.Sprintf(`operator "%s" is invalid`, b.Op)})
+}
+
+// number returns a number as an integer, sign or zero extended to 64 bits
+// like its Go type says, and as a float. isFloat tells which one it really
+// is.
+func number(v interface{}) (i uint64, f float64, isFloat bool) {
+	switch v := v.(type) {
+	case int8:
+		return uint64(v), float64(v), false
+	case int16:
+		return uint64(v), float64(v), false
+	case int32:
+		return uint64(v), float64(v), false
+	case int64:
+		return uint64(v), float64(v), false
+	case uint8:
+		return uint64(v), float64(v), false
+	case uint16:
+		return uint64(v), float64(v), false
+	case uint32:
+		return uint64(v), float64(v), false
+	case uint64:
+		return v, float64(v), false
+	case float32:
+		return 0, float64(v), true
+	case float64:
+		return 0, v, true
+	}
+	panic(fmt.Sprintf("%T is not a number", v))
+}
+
+// castValue returns a number or a char as a value of the type to, truncating,
+// extending or rounding it like the cast instructions of LLVM do.
+func castValue(v interface{}, to string) interface{} {
+	i, f, isFloat := number(v)
+	info, _ := castType(to)
+	if info.float {
+		if info.bits == 32 {
+			return float32(f)
+		}
+		return f
+	}
+
+	if isFloat {
+		if info.signed {
+			i = uint64(int64(f))
+		} else {
+			i = uint64(f)
+		}
+	}
+	switch info.bits {
+	case 8:
+		if info.signed {
+			return int8(i)
+		}
+		return uint8(i)
+	case 16:
+		if info.signed {
+			return int16(i)
+		}
+		return uint16(i)
+	case 32:
+		if info.signed {
+			return int32(i)
+		}
+		return uint32(i)
+	}
+	if info.signed {
+		return int64(i)
+	}
+	return i
+}
+
+func (in *Interpreter) evalBinary(b *BinaryAST, l, r interface{}) interface{} {
+	// Integers of any width are computed on 64 bits and wrapped again
+	if info, ok := numberTypes[typeOf(b.Lhs)]; ok && !info.float {
+		return in.evalInteger(b, info, l, r)
+	}
+
+	switch l := l.(type) {
+	case float32:
+		result := in.evalBinary(b, float64(l), float64(r.(float32)))
+		if f, ok := result.(float64); ok {
+			return float32(f)
+		}
+		return result
+	case float64:
+		r := r.(float64)
+		switch b.Op {
+		case "+":
+			return l + r
+		case "-":
+			return l - r
+		case "*":
+			return l * r
+		case "/":
+			if r == 0 && os.Getenv("PRELUDE") != "empty" {
+				in.divisionByZero()
+			}
+			return l / r
+		case "%":
+			if r == 0 && os.Getenv("PRELUDE") != "empty" {
+				in.divisionByZero()
+			}
+			return math.Mod(l, r)
+		case "<":
+			return l < r
+		case "<=":
+			return l <= r
+		case ">":
+			return l > r
+		case ">=":
+			return l >= r
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		}
+	case string:
+		switch b.Op {
+		case "+":
+			return l + r.(string)
+		case "==":
+			return l == r.(string)
+		case "!=":
+			return l != r.(string)
+		case "<":
+			return l < r.(string)
+		case "<=":
+			return l <= r.(string)
+		case ">":
+			return l > r.(string)
+		case ">=":
+			return l >= r.(string)
+		}
+	case byte:
+		r := r.(byte)
+		switch b.Op {
+		case "<":
+			return l < r
+		case "<=":
+			return l <= r
+		case ">":
+			return l > r
+		case ">=":
+			return l >= r
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		}
+	case bool:
+		switch b.Op {
+		case "==":
+			return l == r.(bool)
+		case "!=", "^":
+			return l != r.(bool)
+		case "&":
+			return l && r.(bool)
+		case "|":
+			return l || r.(bool)
+		}
+	}
+
+	panic(runtimeError{b.Pos, fmt.Sprintf(`operator "%s" is invalid`, b.Op)})
+}
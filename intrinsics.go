@@ -0,0 +1,33 @@
+package main
+
+// intrinsics are functions every program can call without declaring them.
+// The backends implement them directly instead of calling a definition.
+var intrinsics = []PrototypeAST{
+	// identical tells whether two strings are the same object, unlike '=='
+	// which compares their contents.
+	{
+		kind: astPrototype,
+		Name: "identical",
+		Args: []ArgsPrototype{
+			{Name: "a", ArgType: LitString},
+			{Name: "b", ArgType: LitString},
+		},
+		ReturnType: LitBool,
+	},
+	// str returns a new string holding the character c, so that characters
+	// can be concatenated with strings.
+	{
+		kind:       astPrototype,
+		Name:       LitString,
+		Args:       []ArgsPrototype{{Name: "c", ArgType: LitChar}},
+		ReturnType: LitString,
+	},
+	// len returns the number of elements of an array or bytes of a string.
+	// The checker checks its argument, which has no single type.
+	{
+		kind:       astPrototype,
+		Name:       "len",
+		Args:       []ArgsPrototype{{Name: "value"}},
+		ReturnType: LitInt,
+	},
+}
@@ -12,20 +12,24 @@ import (
 type Token int
 
 const (
-	TokEOF        Token = iota // End of string/file
-	TokIdentifier              // Identifier
-	TokNumber                  // number
-	TokStr                     // string
-	TokLParen                  // (
-	TokRParen                  // )
-	TokLBrace                  // {
-	TokRBrace                  // }
-	TokEqual                   // ==
-	TokAssign                  // =
-	TokTypeSpec				   // : Used for specifying a type
-	TokArgSep				   // , arg separator
-	TokAttribute               // #[attr1 = 0]
-	TokAtom                    // :atom
+	TokEOF            Token = iota // End of string/file
+	TokIdentifier                  // Identifier
+	TokNumber                      // number
+	TokStr                         // string
+	TokChar                        // 'c' character
+	TokLParen                      // (
+	TokRParen                      // )
+	TokLBrace                      // {
+	TokRBrace                      // }
+	TokEqual                       // ==
+	TokAssign                      // =
+	TokTypeSpec                    // : Used for specifying a type
+	TokArgSep                      // , arg separator
+	TokAttribute                   // #[attr1 = 0]
+	TokAtom                        // :atom
+	TokRange                       // .. half-open range
+	TokRangeInclusive              // ..= inclusive range
+	TokArrow                       // => match arm
 
 	KWBegin
 	TokIn       // loop - element in array
@@ -38,34 +42,54 @@ const (
 	TokUnknown  // Not specified type
 	TokReturn   // procedure return
 	TokFunction // function
+	TokLet      // immutable variable
+	TokVar      // mutable variable
+	TokBreak    // leave a loop
+	TokContinue // next loop iteration
+	TokStruct   // struct declaration
+	TokEnum     // enum declaration
+	TokMatch    // match expression
+	TokAs       // cast
 	KWEnd
 )
 
 var tokens = map[Token]string{
-	TokAtom:       "ATOM",
-	TokUnknown:    "UNKNOWN",
-	TokEOF:        "EOF",
-	TokIdentifier: "IDENT",
-	TokNumber:     "NUMBER",
-	TokStr:        "STRING",
-	TokAttribute:  "ATTRIBUTE",
-	TokExtern:     "@",
-	TokFunction:   "fun",
-	TokReturn:     "return",
-	TokTrue:       "true",
-	TokFalse:      "false",
-	TokIf:         "if",
-	TokElse:       "else",
-	TokForLoop:    "for",
-	TokIn:         "in",
-	TokLParen:     "(",
-	TokRParen:     ")",
-	TokLBrace:     "{",
-	TokRBrace:     "}",
-	TokEqual:      "==",
-	TokAssign:     "=",
-	TokTypeSpec:   ":",
-	TokArgSep:     ",",
+	TokAtom:           "ATOM",
+	TokUnknown:        "UNKNOWN",
+	TokEOF:            "EOF",
+	TokIdentifier:     "IDENT",
+	TokNumber:         "NUMBER",
+	TokStr:            "STRING",
+	TokChar:           "CHAR",
+	TokAttribute:      "ATTRIBUTE",
+	TokExtern:         "@",
+	TokFunction:       "fun",
+	TokLet:            "let",
+	TokVar:            "var",
+	TokBreak:          "break",
+	TokContinue:       "continue",
+	TokStruct:         "struct",
+	TokEnum:           "enum",
+	TokMatch:          "match",
+	TokAs:             "as",
+	TokReturn:         "return",
+	TokTrue:           "true",
+	TokFalse:          "false",
+	TokIf:             "if",
+	TokElse:           "else",
+	TokForLoop:        "for",
+	TokIn:             "in",
+	TokLParen:         "(",
+	TokRParen:         ")",
+	TokLBrace:         "{",
+	TokRBrace:         "}",
+	TokEqual:          "==",
+	TokAssign:         "=",
+	TokTypeSpec:       ":",
+	TokArgSep:         ",",
+	TokRange:          "..",
+	TokRangeInclusive: "..=",
+	TokArrow:          "=>",
 }
 
 var keywords map[string]Token
@@ -85,6 +109,7 @@ func Lookup(identifier string) Token {
 }
 
 type Lexer struct {
+	file          string
 	source        string
 	token         Token
 	identifier    string
@@ -94,20 +119,24 @@ type Lexer struct {
 	offsetChar    int
 	forwardOffset int
 	pos           Pos
+	tokStart      Pos // position of the first character of the token
+	tokEnd        Pos // position right after the token
 	lastChar      rune
 	isEOF         bool
 	ignoreNewLine bool
 	ignoreSpace   bool
 	ignoreAtoms   bool
 	isFloat       bool
+	extraDot      bool // the number has more than one '.'
 }
 
-func NewLexer(source string) Lexer {
+func NewLexer(file, source string) Lexer {
 	lexer := Lexer{
+		file:          file,
 		source:        source,
 		offsetChar:    0,
 		forwardOffset: 0,
-		pos:           Pos{col: 1, row: 0},
+		pos:           Pos{file: file, row: 1, col: 0},
 		ignoreNewLine: true,
 		ignoreSpace:   true,
 	}
@@ -171,17 +200,13 @@ func (l *Lexer) removeSpace() (stopLexing bool) {
 }
 
 func (l *Lexer) isAlphabetic() (stopLexing bool) {
-	if unicode.IsLetter(l.lastChar) {
+	if unicode.IsLetter(l.lastChar) || l.lastChar == '_' {
 		l.identifier = string(l.lastChar)
 
-		if l.nextChar() != nil {
-			return true
-		}
-		for unicode.IsLetter(l.lastChar) || l.lastChar == '_' {
+		l.isEOF = l.nextChar() != nil
+		for !l.isEOF && (unicode.IsLetter(l.lastChar) || unicode.IsDigit(l.lastChar) || l.lastChar == '_') {
 			l.identifier += string(l.lastChar)
-			if l.nextChar() != nil {
-				break
-			}
+			l.isEOF = l.nextChar() != nil
 		}
 
 		l.token = Lookup(l.identifier)
@@ -191,23 +216,28 @@ func (l *Lexer) isAlphabetic() (stopLexing bool) {
 }
 
 func (l *Lexer) isDigit() (stopLexing bool) {
-	if unicode.IsNumber(l.lastChar) || l.lastChar == '.' || l.lastChar == '-' {
+	// Signs are unary operators, so "a -1" is a subtraction
+	startsFraction := l.lastChar == '.' && l.peek() >= '0' && l.peek() <= '9'
+	if unicode.IsNumber(l.lastChar) || startsFraction {
 		tempStr := ""
 		l.isFloat = l.lastChar == '.'
+		l.extraDot = false
 
 		for {
 			tempStr += string(l.lastChar)
 			if l.nextChar() != nil {
-				l.token = TokEOF
-				return true
+				l.isEOF = true
+				break
+			}
+
+			// "1..5" is a range, not the number "1."
+			if l.lastChar == '.' && l.peek() == '.' {
+				break
 			}
 
 			if l.lastChar == '.' {
-				if l.isFloat {
-					panic("Invalid use of '.'")
-				} else {
-					l.isFloat = true
-				}
+				l.extraDot = l.extraDot || l.isFloat
+				l.isFloat = true
 			}
 
 			if !unicode.IsNumber(l.lastChar) && l.lastChar != 46 {
@@ -223,6 +253,26 @@ func (l *Lexer) isDigit() (stopLexing bool) {
 	return false
 }
 
+func (l *Lexer) isRange() (stopLexing bool) {
+	if l.lastChar == '.' && l.peek() == '.' {
+		_ = l.nextChar()
+		if l.nextChar() != nil {
+			l.isEOF = true
+			l.token = TokRange
+			return true
+		}
+
+		l.token = TokRange
+		if l.lastChar == '=' {
+			l.isEOF = l.nextChar() != nil
+			l.token = TokRangeInclusive
+		}
+		return true
+	}
+
+	return false
+}
+
 func (l *Lexer) isComment() (stopLexing bool) {
 	if l.lastChar == '/' {
 		ch := l.peek()
@@ -329,16 +379,7 @@ func (l *Lexer) isStr() (stopLexing bool) {
 			}
 		}
 
-		l.strVal = strings.ReplaceAll(l.strVal, "\\r", "\r")
-		l.strVal = strings.ReplaceAll(l.strVal, "\\n", "\n")
-		l.strVal = strings.ReplaceAll(l.strVal, "\\b", "\b")
-		l.strVal = strings.ReplaceAll(l.strVal, "\\a", "\a")
-		l.strVal = strings.ReplaceAll(l.strVal, "\\f", "\f")
-		l.strVal = strings.ReplaceAll(l.strVal, "\\t", "\t")
-		l.strVal = strings.ReplaceAll(l.strVal, "\\v", "\v")
-		l.strVal = strings.ReplaceAll(l.strVal, "\\\\", "\\")
-		l.strVal = strings.ReplaceAll(l.strVal, "\\\"", "\"")
-
+		l.strVal = unescape(l.strVal)
 		l.isEOF = l.nextChar() != nil
 		l.token = TokStr
 		return true
@@ -347,6 +388,53 @@ func (l *Lexer) isStr() (stopLexing bool) {
 	return false
 }
 
+// isChar scans a character literal into strVal. The parser checks that it
+// holds exactly one byte.
+func (l *Lexer) isChar() (stopLexing bool) {
+	if l.lastChar == '\'' {
+		l.strVal = ""
+		for {
+			if l.nextChar() != nil {
+				l.isEOF = true
+				break
+			}
+			if l.lastChar == '\'' || l.lastChar == 10 || l.lastChar == 13 {
+				break
+			}
+
+			l.strVal += string(l.lastChar)
+			if l.lastChar == '\\' && l.peek() != 0 {
+				_ = l.nextChar()
+				l.strVal += string(l.lastChar)
+			}
+		}
+		if l.lastChar == '\'' {
+			l.isEOF = l.nextChar() != nil
+		}
+
+		l.strVal = unescape(l.strVal)
+		l.token = TokChar
+		return true
+	}
+
+	return false
+}
+
+// unescape replaces the escape sequences of string and character literals.
+func unescape(s string) string {
+	s = strings.ReplaceAll(s, "\\r", "\r")
+	s = strings.ReplaceAll(s, "\\n", "\n")
+	s = strings.ReplaceAll(s, "\\b", "\b")
+	s = strings.ReplaceAll(s, "\\a", "\a")
+	s = strings.ReplaceAll(s, "\\f", "\f")
+	s = strings.ReplaceAll(s, "\\t", "\t")
+	s = strings.ReplaceAll(s, "\\v", "\v")
+	s = strings.ReplaceAll(s, "\\'", "'")
+	s = strings.ReplaceAll(s, "\\\\", "\\")
+	s = strings.ReplaceAll(s, "\\\"", "\"")
+	return s
+}
+
 func (l *Lexer) isEqual() (stopLexing bool) {
 	if l.lastChar == '=' {
 		if l.nextChar() != nil {
@@ -354,6 +442,12 @@ func (l *Lexer) isEqual() (stopLexing bool) {
 			return true
 		}
 
+		if l.lastChar == '>' {
+			l.isEOF = l.nextChar() != nil
+			l.token = TokArrow
+			return true
+		}
+
 		if l.lastChar != '=' {
 			l.token = TokAssign
 			return true
@@ -409,6 +503,7 @@ func (l *Lexer) isAtom() (stopLexing bool) {
 		oldToken := l.token
 		oldOffset := l.offsetChar
 		oldFwOffset := l.forwardOffset
+		oldPos := l.pos
 
 		l.ignoreSpace = false
 		l.ignoreNewLine = false
@@ -425,6 +520,7 @@ func (l *Lexer) isAtom() (stopLexing bool) {
 			atom += string(l.lastChar)
 
 			if l.nextChar() != nil {
+				l.isEOF = true
 				break
 			}
 		}
@@ -437,6 +533,7 @@ func (l *Lexer) isAtom() (stopLexing bool) {
 			l.token = oldToken
 			l.offsetChar = oldOffset
 			l.forwardOffset = oldFwOffset
+			l.pos = oldPos
 			return false
 		}
 
@@ -448,16 +545,37 @@ func (l *Lexer) isAtom() (stopLexing bool) {
 	return false
 }
 
+// tokenText describes the current token for error messages.
+func (l *Lexer) tokenText() string {
+	if l.token == TokUnknown {
+		return string(l.unknownVal)
+	}
+	return tokens[l.token]
+}
+
 func (l *Lexer) nextToken() {
+	l.scanToken()
+	l.tokEnd = l.pos
+	// At the end of the source pos stays on the last character of the token
+	if l.isEOF && l.token != TokEOF {
+		l.tokEnd.col++
+	}
+}
+
+func (l *Lexer) scanToken() {
 	if l.isEOF {
 		l.token = TokEOF
+		l.tokStart = l.pos
 		return
 	}
 
 	if l.removeSpace() {
+		l.tokStart = l.pos
 		return
 	}
 
+	l.tokStart = l.pos
+
 	if l.isAttribute() {
 		return
 	}
@@ -474,10 +592,18 @@ func (l *Lexer) nextToken() {
 		return
 	}
 
+	if l.isChar() {
+		return
+	}
+
 	if l.isDigit() {
 		return
 	}
 
+	if l.isRange() {
+		return
+	}
+
 	if l.isEqual() {
 		return
 	}
@@ -1,46 +1,160 @@
 package main
 
 import (
-	"novum-lang/llvm/bindings/go/llvm"
+	"fmt"
+	"math"
 	"strconv"
+	"strings"
 )
 
 const (
 	LitVoid   = "void"
 	LitString = "str"
 	LitFloat  = "float"
-	LitBool	  = "bool"
-	LitInt	  = "int"
+	LitBool   = "bool"
+	LitInt    = "int"
+	LitChar   = "char"
+	LitAtom   = "atom"
 )
 
-func ASTTypeToLit(astType kind) string {
-	switch astType {
-	case astString:
-		return LitString
-	case astNumberFloat:
-		return LitFloat
-	case astBool:
-		return LitBool
-	case astNumberInt:
-		return LitInt
+// numberType describes how a numeric type is represented.
+type numberType struct {
+	bits   int
+	signed bool
+	float  bool
+}
+
+// numberTypes lists the numeric types. int is 32 bits wide and float 64, like
+// i32 and f64, but they are types of their own.
+var numberTypes = map[string]numberType{
+	LitInt:   {32, true, false},
+	"i8":     {8, true, false},
+	"i16":    {16, true, false},
+	"i32":    {32, true, false},
+	"i64":    {64, true, false},
+	"u8":     {8, false, false},
+	"u16":    {16, false, false},
+	"u32":    {32, false, false},
+	"u64":    {64, false, false},
+	LitFloat: {64, true, true},
+	"f32":    {32, true, true},
+	"f64":    {64, true, true},
+}
+
+// holds tells whether the integer or float v is a value of the type.
+func (n numberType) holds(v float64) bool {
+	switch {
+	case n.float:
+		return true
+	case n.signed:
+		limit := math.Exp2(float64(n.bits - 1))
+		return v >= -limit && v < limit
+	default:
+		return v >= 0 && v < math.Exp2(float64(n.bits))
 	}
+}
+
+// numberText spells a number literal without an exponent.
+func numberText(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
 
-	panic("Type of index '" + strconv.Itoa(int(astType)) + "' does not exist as literal")
+// castType returns the representation of a type that can be cast: a number
+// or a char, which is a byte like u8.
+func castType(t string) (numberType, bool) {
+	if t == LitChar {
+		return numberTypes["u8"], true
+	}
+	info, ok := numberTypes[t]
+	return info, ok
 }
 
-func LLVMTypeToLit(llvmType llvm.Type) string {
-	switch llvmType {
-	case llvm.PointerType(llvm.Int8Type(), 0):
-		return LitString
-	case llvm.DoubleType():
-		return LitFloat
-	case llvm.Int1Type():
-		return LitBool
-	case llvm.Int32Type():
-		return LitInt
+// widens tells whether every value of the numeric type from is also a value
+// of the numeric type to, so that it converts implicitly. Integers and floats
+// never convert to each other implicitly.
+func widens(to, from string) bool {
+	t, ok := numberTypes[to]
+	f, fromOk := numberTypes[from]
+	if !ok || !fromOk || t.float != f.float {
+		return false
 	}
+	if t.float || t.signed == f.signed {
+		return t.bits >= f.bits
+	}
+	return t.signed && t.bits > f.bits
+}
+
+// Array types are spelled like in the source: "[int; 4]" has a fixed length
+// and "[]int" any.
+
+func arrayType(elem string, length int) string {
+	return fmt.Sprintf("[%s; %d]", elem, length)
+}
 
-	panic("Type of index '" + llvmType.String() + "' does not exist as literal")
+func sliceType(elem string) string {
+	return "[]" + elem
 }
 
+// elemType returns the element type of an array type, and the length for
+// arrays of a fixed length or -1. ok is false for other types.
+func elemType(t string) (elem string, length int, ok bool) {
+	if strings.HasPrefix(t, "[]") {
+		return t[2:], -1, true
+	}
+	if !strings.HasPrefix(t, "[") || !strings.HasSuffix(t, "]") {
+		return "", 0, false
+	}
 
+	sep := strings.LastIndex(t, "; ")
+	if sep < 0 {
+		return "", 0, false
+	}
+	length, err := strconv.Atoi(t[sep+2 : len(t)-1])
+	if err != nil {
+		return "", 0, false
+	}
+	return t[1:sep], length, true
+}
+
+// assignable tells whether a value of type from can be used where type to is
+// expected. Arrays of a fixed length can be used as arrays of any length.
+// Both are the same at run time, where indexing checks the actual length.
+func assignable(to, from string) bool {
+	if to == from {
+		return true
+	}
+
+	toElem, toLen, ok := elemType(to)
+	if !ok || toLen >= 0 {
+		return false
+	}
+	fromElem, _, ok := elemType(from)
+	return ok && assignable(toElem, fromElem)
+}
+
+// converts tells whether a value of type from can be used where type to is
+// expected, maybe after converting it implicitly.
+func converts(to, from string) bool {
+	return assignable(to, from) || widens(to, from)
+}
+
+// commonType returns the type both types can be used as, or "" if there is
+// none. Arrays of different lengths have the type of arrays of any length.
+func commonType(a, b string) string {
+	switch {
+	case assignable(a, b):
+		return a
+	case assignable(b, a):
+		return b
+	}
+
+	aElem, _, aOk := elemType(a)
+	bElem, _, bOk := elemType(b)
+	if !aOk || !bOk {
+		return ""
+	}
+	if elem := commonType(aElem, bElem); elem != "" {
+		return sliceType(elem)
+	}
+	return ""
+}
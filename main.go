@@ -1,94 +1,42 @@
 package main
 
 import (
-	"io/ioutil"
-	"novum-lang/llvm/bindings/go/llvm"
+	"os"
 )
 
-func handleFunction(parser *Parser, init bool) {
-	functionAST := parser.parseFunction(init)
-	if init {
-		protoIR := functionAST.Proto.codegen()
-		if protoIR.IsNil() {
-			panic("Proto CodeGen Error: Could not create IR")
-		}
-	} else {
-		fcIR := functionAST.codegen()
-		if fcIR.IsNil() {
-			panic("Function CodeGen Error: Could not create IR")
+// parseSources parses every source in two passes. The first one only reads
+// prototypes and attributes, so functions and operators can be used before
+// (or in another file than) the place they are defined. Its errors are
+// dropped because the second pass reports them again.
+func parseSources(sources []SourceFile) ([]AST, []Diagnostic) {
+	parsers := make([]*Parser, 0, len(sources))
+	for _, source := range sources {
+		parser := NewParser(source.Name, source.Text)
+		if len(parsers) != 0 {
+			parser.binOpPrecedence = parsers[0].binOpPrecedence
+			parser.enums = parsers[0].enums
 		}
+		parsers = append(parsers, &parser)
 	}
-}
-
-func handleExtern(parser *Parser, init bool) {
-	protoAST := parser.parseExtern()
 
-	if init {
-		externIR := protoAST.codegen()
-		if externIR.IsNil() {
-			panic("Extern CodeGen Error: Could not create IR")
-		}
+	for _, parser := range parsers {
+		parser.initialize = true
+		parser.parseModule()
 	}
-}
 
-func handleTopLevelExpression(parser *Parser, init bool) {
-	//topAST, err := parser.ParseTopLevelExpr()
-	//if err != nil {
-	//	panic("Top Level Expression Parse Error: " + err.Error())
-	//}
-	//
-	//if !init {
-	//	topIR := topAST.codegen()
-	//	if topIR.IsNil() {
-	//		panic("Top Level Expression CodeGen Error: Could not create IR")
-	//	}
-	//}
-}
-
-func handle(parser Parser, init bool) {
-	switch parser.lexer.token {
-	case TokEOF:
-		return
-	case TokFunction:
-		handleFunction(&parser, init)
-	case TokExtern:
-		handleExtern(&parser, init)
-	case TokAttribute:
-		parser.parseAttribute()
-	default:
-		if init {
-			parser.lexer.nextToken()
-		}
-		handleTopLevelExpression(&parser, init)
+	var decls []AST
+	var errs []Diagnostic
+	for i, parser := range parsers {
+		parser.initialize = false
+		parser.errors = nil
+		parser.lexer = NewLexer(sources[i].Name, sources[i].Text)
+		decls = append(decls, parser.parseModule()...)
+		errs = append(errs, parser.errors...)
 	}
 
-	handle(parser, init)
-}
-
-func initParser(data string) {
-	parser := NewParser(data)
-
-	parser.initialize = true
-	handle(parser, true)
-	parser.initialize = false
-
-	parser.lexer = NewLexer(data)
-	handle(parser, false)
+	return decls, errs
 }
 
 func main() {
-	InitModuleAndPassManager()
-
-	data, err := ioutil.ReadFile("./test.nv")
-	if err != nil {
-		panic(err.Error())
-	}
-
-	initParser(string(data))
-
-	if llvm.VerifyModule(module, llvm.PrintMessageAction) != nil {
-		panic("Failed to verify module")
-	}
-
-	module.Dump()
+	os.Exit(runDriver(os.Args[1:]))
 }
@@ -0,0 +1,101 @@
+//go:build llvm
+// +build llvm
+
+package main
+
+import (
+	"io"
+	"io/ioutil"
+	"novum-lang/llvm/bindings/go/llvm"
+	"os"
+)
+
+// generateModule declares every prototype before generating any function
+// body, so calls don't depend on the order of declarations. Externs the
+// target can't call are reported instead.
+func generateModule(decls []AST) []Diagnostic {
+	declareStructs(decls)
+
+	var diags []Diagnostic
+	for _, decl := range decls {
+		var fc llvm.Value
+		switch d := decl.(type) {
+		case *FunctionAST:
+			fc = d.Proto.codegen()
+		case *PrototypeAST:
+			var err error
+			if fc, err = declareExtern(d); err != nil {
+				end := d.Pos
+				end.col += len(d.Name)
+				diags = append(diags, Diagnostic{
+					Severity: SeverityError,
+					Code:     ErrCodegen,
+					Start:    d.Pos,
+					End:      end,
+					Message:  err.Error(),
+					Notes:    []string{"structs, enums and arrays are only passed to externs on x86-64 and AArch64"},
+				})
+				continue
+			}
+		default:
+			continue
+		}
+
+		if fc.IsNil() {
+			panic("Proto CodeGen Error: Could not create IR")
+		}
+	}
+	if len(diags) > 0 {
+		return diags
+	}
+
+	for _, decl := range decls {
+		if function, ok := decl.(*FunctionAST); ok {
+			if function.codegen().IsNil() {
+				panic("Function CodeGen Error: Could not create IR")
+			}
+		}
+	}
+	return nil
+}
+
+// generateNative builds and optimizes the LLVM module of the checked
+// declarations.
+func generateNative(opts Options, decls []AST) []Diagnostic {
+	InitModuleAndPassManager(opts.OptLevel)
+	if err := initTarget(opts.Target, opts.OptLevel); err != nil {
+		return []Diagnostic{{Severity: SeverityError, Message: err.Error()}}
+	}
+
+	if diags := generateModule(decls); len(diags) > 0 {
+		return diags
+	}
+
+	if verifyModule() != nil {
+		return []Diagnostic{{
+			Severity: SeverityError,
+			Code:     ErrCodegen,
+			Message:  "failed to verify module",
+		}}
+	}
+
+	optimizeModule(opts.OptLevel)
+	return nil
+}
+
+func writeOutput(opts Options) error {
+	switch opts.Emit {
+	case EmitIR:
+		if opts.Output == "-" {
+			_, err := io.WriteString(os.Stdout, module.String())
+			return err
+		}
+		return ioutil.WriteFile(opts.Output, []byte(module.String()), 0644)
+	case EmitBC:
+		return writeBitcode(opts.Output)
+	case EmitASM, EmitObj:
+		return emitNative(opts.Emit, opts.Output)
+	}
+
+	return buildExecutable(opts.Output)
+}
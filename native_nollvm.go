@@ -0,0 +1,21 @@
+//go:build !llvm
+// +build !llvm
+
+package main
+
+import (
+	"errors"
+)
+
+// codegenAST is empty without LLVM, see codegen.go.
+type codegenAST interface{}
+
+var errNoLLVM = errors.New("novum was built without LLVM support, rebuild it with '-tags llvm' or use 'run --interp'")
+
+func generateNative(opts Options, decls []AST) []Diagnostic {
+	return []Diagnostic{{Severity: SeverityError, Message: errNoLLVM.Error()}}
+}
+
+func writeOutput(opts Options) error {
+	return errNoLLVM
+}
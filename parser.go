@@ -5,24 +5,25 @@ import (
 	"strings"
 )
 
-var BinOpLookup = make(map[string][]ArgsPrototype)
-
 type Parser struct {
 	lexer             Lexer
 	defaultPrecedence int
 	isOperator        bool
 	isBinaryOp        bool
 	binOpPrecedence   map[string]int
-	knownVars         map[string]string
-	initialize		  bool
-	errors			  []string
+	initialize        bool
+	noStructLit       bool            // in the condition of an if or a loop
+	enums             map[string]bool // declared in any of the files
+	errors            []Diagnostic
 }
 
-func NewParser(data string) Parser {
+func NewParser(file, data string) Parser {
 	return Parser{
-		lexer: NewLexer(data),
+		lexer: NewLexer(file, data),
+		enums: map[string]bool{},
 		binOpPrecedence: map[string]int{
-			"=":  2,
+			"||": 5,
+			"&&": 6,
 			"==": 9,
 			"!=": 9,
 			"<":  10,
@@ -31,45 +32,189 @@ func NewParser(data string) Parser {
 			"<=": 10,
 			"+":  20,
 			"-":  20,
+			"|":  20,
+			"^":  20,
 			"*":  40,
 			"/":  40,
+			"%":  40,
+			"&":  40,
+			"<<": 40,
+			">>": 40,
 		},
 	}
 }
 
-func (p *Parser) addError(err string) {
-	if !p.initialize {
-		panic(err)
+// bailout unwinds the parser to the closest recovery point after a syntax
+// error. It never leaves the parser.
+type bailout struct{}
+
+func (p *Parser) errorAt(start, end Pos, code, err string, notes ...string) {
+	p.errors = append(p.errors, Diagnostic{
+		Severity: SeverityError,
+		Code:     code,
+		Start:    start,
+		End:      end,
+		Message:  err,
+		Notes:    notes,
+	})
+}
+
+// addError records an error at the current token that the parser can
+// continue after.
+func (p *Parser) addError(code, err string, notes ...string) {
+	p.errorAt(p.lexer.tokStart, p.lexer.tokEnd, code, err, notes...)
+}
+
+// syntaxError records an error at the current token and abandons the current
+// statement or declaration. Parsing resumes at the next boundary.
+func (p *Parser) syntaxError(code, err string, notes ...string) {
+	p.addError(code, err, notes...)
+	panic(bailout{})
+}
+
+func (p *Parser) atDeclaration() bool {
+	switch p.lexer.token {
+	case TokEOF, TokFunction, TokExtern, TokAttribute, TokStruct, TokEnum:
+		return true
+	}
+	return false
+}
+
+// recoverStatement skips the rest of a broken statement. It stops after a
+// nested block, before a statement keyword or the '}' closing the current
+// block, or at a declaration, in which case the whole declaration is
+// abandoned too.
+func (p *Parser) recoverStatement() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if _, ok := r.(bailout); !ok {
+		panic(r)
+	}
+
+	depth := 0
+	skipped := false
+	for !p.atDeclaration() {
+		switch p.lexer.token {
+		case TokLBrace:
+			depth++
+		case TokRBrace:
+			if depth == 0 {
+				return
+			}
+			depth--
+			if depth == 0 {
+				p.lexer.nextToken()
+				return
+			}
+		case TokIf, TokForLoop, TokReturn, TokLet, TokVar, TokBreak, TokContinue, TokMatch:
+			if depth == 0 && skipped {
+				return
+			}
+		}
+		p.lexer.nextToken()
+		skipped = true
+	}
+
+	panic(bailout{})
+}
+
+// recoverDeclaration skips everything up to the next declaration.
+func (p *Parser) recoverDeclaration() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if _, ok := r.(bailout); !ok {
+		panic(r)
+	}
+
+	p.isOperator = false
+	p.isBinaryOp = false
+	p.defaultPrecedence = 0
+	p.lexer.ignoreAtoms = false
+	p.lexer.ignoreNewLine = true
+	p.lexer.ignoreSpace = true
+
+	for !p.atDeclaration() {
+		p.lexer.nextToken()
+	}
+}
+
+// parseModule parses all declarations of the source. Broken statements and
+// declarations are left out of the result and reported in p.errors.
+func (p *Parser) parseModule() []AST {
+	var decls []AST
+	for p.lexer.token != TokEOF {
+		if decl := p.parseDeclaration(); decl != nil {
+			decls = append(decls, decl)
+		}
 	}
-	p.errors = append(p.errors, err)
+	return decls
 }
 
-func (p *Parser) checkType(t string) string {
-	switch t {
-	case LitVoid:
-		return LitVoid
-	case LitFloat:
-		return LitFloat
-	case LitString:
-		return LitString
-	case LitBool:
-		return LitBool
-	case LitInt:
-		return LitInt
+func (p *Parser) parseDeclaration() (decl AST) {
+	defer p.recoverDeclaration()
+
+	switch p.lexer.token {
+	case TokFunction:
+		function := p.parseFunction(p.initialize)
+		return &function
+	case TokExtern:
+		proto := p.parseExtern()
+		return &proto
+	case TokStruct:
+		return p.parseStruct()
+	case TokEnum:
+		return p.parseEnum()
+	case TokAttribute:
+		p.parseAttribute()
+		return nil
 	default:
-		p.addError(fmt.Sprintf("Type %s doesn't exist.", t))
-		return ""
+		// The first pass only reads prototypes and skips function bodies.
+		if p.initialize {
+			p.lexer.nextToken()
+			return nil
+		}
+		p.syntaxError(ErrUnexpectedToken, "'"+p.lexer.tokenText()+"' is not a declaration.")
+		return nil
+	}
+}
+
+// parseStmtInBlock parses one statement, resynchronizing after syntax errors.
+func (p *Parser) parseStmtInBlock() (stmt AST) {
+	defer p.recoverStatement()
+	return p.parseStmt()
+}
+
+// parseBlock parses '{' statements '}'.
+func (p *Parser) parseBlock(what string) BlockAST {
+	pos := p.checkAndNext(TokLBrace)
+	var body []AST
+	for p.lexer.token != TokRBrace {
+		if p.atDeclaration() {
+			p.syntaxError(ErrUnclosed, what+" is not closed.", fmt.Sprintf("it was opened at %d:%d", pos.row, pos.col))
+		}
+
+		stmt := p.parseStmtInBlock()
+		if stmt != nil {
+			body = append(body, stmt)
+		}
+	}
+
+	p.lexer.nextToken()
+	return BlockAST{
+		pos,
+		astBlock,
+		body,
 	}
 }
 
 func (p *Parser) checkAndNext(tok Token) Pos {
-	pos := p.lexer.pos
+	pos := p.lexer.tokStart
 	if p.lexer.token != tok {
-		got := tokens[p.lexer.token]
-		if p.lexer.token == TokUnknown {
-			got = string(p.lexer.unknownVal)
-		}
-		p.addError(fmt.Sprintf("Invalid token. Expected: %s, got: %s", tokens[tok], got))
+		p.syntaxError(ErrUnexpectedToken, fmt.Sprintf("Invalid token. Expected: %s, got: %s", tokens[tok], p.lexer.tokenText()))
 	}
 	p.lexer.nextToken()
 	return pos
@@ -78,39 +223,41 @@ func (p *Parser) checkAndNext(tok Token) Pos {
 func (p *Parser) parseArgs() []ArgsPrototype {
 	var argsNames []ArgsPrototype
 	for {
+		if p.lexer.token == TokEOF {
+			p.syntaxError(ErrUnclosed, "Arguments are not closed.")
+		}
+
 		if p.lexer.token == TokIdentifier {
 			name := p.lexer.identifier
+			namePos := p.lexer.tokStart
 			p.lexer.nextToken()
 
 			if p.lexer.token != TokTypeSpec {
-				p.addError("After '"+name+"' argument there is no type specification.")
+				p.syntaxError(ErrUnexpectedToken, "After '"+name+"' argument there is no type specification.")
 			}
 
 			p.lexer.nextToken()
-			_ = p.checkAndNext(TokIdentifier)
-			varType := p.checkType(p.lexer.identifier)
+			typeName, typePos := p.parseType()
 			argsNames = append(argsNames, ArgsPrototype{
+				Pos:     namePos,
 				Name:    name,
-				ArgType: varType,
+				ArgType: typeName,
+				TypePos: typePos,
 			})
-
-			_, exist := p.knownVars[name]
-			if exist {
-				p.addError("Variable with the same name already exist.")
-			}
-
-			p.knownVars[name] = varType
 		} else if p.lexer.token == TokRParen {
 			if len(argsNames) == 0 {
 				break
 			} else {
-				p.addError("Expected another argument.")
+				p.syntaxError(ErrUnexpectedToken, "Expected another argument.")
 			}
 		}
 
 		if p.lexer.token != TokArgSep {
+			if p.lexer.token == TokEOF {
+				p.syntaxError(ErrUnclosed, "Arguments are not closed.")
+			}
 			if p.lexer.token != TokRParen {
-				p.addError("Expected ')'")
+				p.syntaxError(ErrUnexpectedToken, "Expected ')'")
 			}
 
 			break
@@ -121,11 +268,10 @@ func (p *Parser) parseArgs() []ArgsPrototype {
 	return argsNames
 }
 
-
 func (p *Parser) parsePrototype() PrototypeAST {
 	p.lexer.ignoreAtoms = true
 
-	pos := p.lexer.pos
+	pos := p.lexer.tokStart
 	isOperator := p.isOperator
 	isBinOp := p.isBinaryOp
 	defPrecedence := p.defaultPrecedence
@@ -136,22 +282,41 @@ func (p *Parser) parsePrototype() PrototypeAST {
 
 	funcName := ""
 
+	var receiver []ArgsPrototype
+	if p.lexer.token == TokLParen && !isOperator {
+		p.lexer.nextToken()
+		receiver = p.parseArgs()
+		p.lexer.nextToken()
+		if len(receiver) != 1 {
+			p.errorAt(pos, p.lexer.tokStart, ErrUnexpectedToken, "A method takes exactly one receiver.")
+		}
+		if p.lexer.token != TokIdentifier {
+			p.syntaxError(ErrUnexpectedToken, "Expected a method name.")
+		}
+		pos = p.lexer.tokStart
+	}
+
 	switch p.lexer.token {
+	case TokEOF:
+		p.syntaxError(ErrUnexpectedToken, "Expected a function name.")
 	case TokIdentifier:
 		if isOperator {
-			p.addError("Error: Operator is not a special character")
+			p.addError(ErrOperatorDef, "Operator is not a special character")
 		}
 
 		funcName = p.lexer.identifier
 		p.lexer.nextToken()
 	default:
 		if !isOperator {
-			p.addError("Found: '"+string(p.lexer.unknownVal)+"' but only operators can use special character")
+			p.syntaxError(ErrOperatorDef, "Found: '"+string(p.lexer.unknownVal)+"' but only operators can use special character")
 		}
 
 		p.lexer.ignoreNewLine = false
 		p.lexer.ignoreSpace = false
 		for {
+			if p.lexer.token == TokEOF {
+				p.syntaxError(ErrOperatorDef, "Expected the arguments of the operator.")
+			}
 			if p.lexer.unknownVal == ' ' || p.lexer.unknownVal == '\n' || p.lexer.token == TokLParen {
 				break
 			}
@@ -161,7 +326,7 @@ func (p *Parser) parsePrototype() PrototypeAST {
 			//}
 
 			if p.lexer.token == TokTypeSpec {
-				p.addError("':' can't be used as operator name.")
+				p.syntaxError(ErrOperatorDef, "':' can't be used as operator name.")
 			}
 
 			if p.lexer.token == TokAssign {
@@ -178,14 +343,23 @@ func (p *Parser) parsePrototype() PrototypeAST {
 		p.lexer.ignoreNewLine = true
 		p.lexer.ignoreSpace = true
 
+		// Another definition of the operator, for other types, keeps its
+		// precedence. The first pass sets it, so a different one found by the
+		// second pass was given elsewhere.
 		if isBinOp {
-			if _, found := p.binOpPrecedence[funcName]; found {
-				// TODO: if binOp exists in current scope(!!!!) throw an exception
+			prec, found := p.binOpPrecedence[funcName]
+			switch {
+			case !found:
+				p.binOpPrecedence[funcName] = defPrecedence
+			case !p.initialize && defPrecedence != 0 && prec != defPrecedence:
+				p.errorAt(pos, p.lexer.tokStart, ErrOperatorDef,
+					fmt.Sprintf("Operator '%s' already has precedence %d.", funcName, prec),
+					"every definition of an operator has the same precedence, it can be left out")
 			}
-			p.binOpPrecedence[funcName] = defPrecedence
+			defPrecedence = p.binOpPrecedence[funcName]
 		} else {
 			if len(funcName) != 1 {
-				p.addError("Error: Unary operator can only have one character name")
+				p.errorAt(pos, p.lexer.tokStart, ErrOperatorDef, "Unary operator can only have one character name")
 			}
 		}
 
@@ -208,47 +382,59 @@ func (p *Parser) parsePrototype() PrototypeAST {
 	}
 
 	if isOperator && isBinOp && len(argsNames) != 2 {
-		p.addError("Wrong number of arguments in the binary operator (" + funcName + ")")
+		p.errorAt(pos, p.lexer.tokStart, ErrOperatorDef, "Wrong number of arguments in the binary operator ("+funcName+")")
 	}
 
 	if isOperator && !isBinOp && len(argsNames) != 1 {
-		p.addError("Wrong number of arguments in the unary operator (" + funcName + ")")
-	}
-
-	if isBinOp {
-		BinOpLookup[funcName] = argsNames
+		p.errorAt(pos, p.lexer.tokStart, ErrOperatorDef, "Wrong number of arguments in the unary operator ("+funcName+")")
 	}
 
-	returnType := LitVoid
+	// The caller decides what a missing return type means
+	returnType := ""
+	returnPos := pos
 	if p.lexer.token == TokTypeSpec {
 		p.lexer.nextToken()
 
-		if p.lexer.token != TokIdentifier {
-			p.addError("Expected a return type.")
+		if p.lexer.token != TokIdentifier && (p.lexer.token != TokUnknown || p.lexer.unknownVal != '[') {
+			p.syntaxError(ErrUnexpectedToken, "Expected a return type.")
 		}
 
-		returnType = p.checkType(p.lexer.identifier)
-		p.lexer.nextToken()
+		returnType, returnPos = p.parseType()
+	}
+
+	receiverType := ""
+	if len(receiver) != 0 {
+		receiverType = receiver[0].ArgType
+		funcName = methodName(receiverType, funcName)
+		argsNames = append([]ArgsPrototype{receiver[0]}, argsNames...)
 	}
 
 	p.lexer.ignoreAtoms = false
 	return PrototypeAST{
-		pos,
-		astPrototype,
-		funcName,
-		argsNames,
-		isOperator,
-		isBinOp,
-		defPrecedence,
-		returnType,
+		Pos:           pos,
+		kind:          astPrototype,
+		Name:          funcName,
+		Args:          argsNames,
+		IsOperator:    isOperator,
+		IsBinaryOp:    isBinOp,
+		Precedence:    defPrecedence,
+		ReturnType:    returnType,
+		ReturnTypePos: returnPos,
+		Receiver:      receiverType,
 	}
 }
 
+// parseFunction parses a function with a block, or with '=' and an expression
+// it returns. The return type of the latter can be left out, the checker
+// infers it from the expression.
 func (p *Parser) parseFunction(init bool) FunctionAST {
 	pos := p.checkAndNext(TokFunction)
-	p.knownVars = make(map[string]string)
 	proto := p.parsePrototype()
 
+	if p.lexer.token != TokAssign && proto.ReturnType == "" {
+		proto.ReturnType = LitVoid
+	}
+
 	if init {
 		return FunctionAST{
 			Pos:   pos,
@@ -258,34 +444,31 @@ func (p *Parser) parseFunction(init bool) FunctionAST {
 		}
 	}
 
-	blockPos := p.checkAndNext(TokLBrace)
-	var body []AST
-	for p.lexer.token != TokRBrace {
-		if p.lexer.token == TokEOF {
-			p.addError("Function is not closed.")
+	if p.lexer.token == TokAssign {
+		bodyPos := p.lexer.tokStart
+		p.lexer.nextToken()
+		body := p.parseExpression()
+		if body == nil {
+			p.syntaxError(ErrUnexpectedToken, "Expected the expression the function returns.")
 		}
-
-		stmt := p.parseStmt()
-		if stmt != nil {
-			body = append(body, stmt)
+		return FunctionAST{
+			pos,
+			astFunction,
+			proto,
+			BlockAST{bodyPos, astBlock, []AST{&ReturnAST{body.Position(), astReturn, body, false}}},
 		}
 	}
 
+	block := p.parseBlock("Function")
 	if proto.ReturnType == LitVoid {
-		body = append(body, &ReturnAST{
-			blockPos,
+		block.Elements = append(block.Elements, &ReturnAST{
+			block.Pos,
 			astReturn,
 			nil,
+			true,
 		})
 	}
 
-	p.lexer.nextToken()
-
-	block := BlockAST{
-		blockPos,
-		astBlock,
-		body,
-	}
 	return FunctionAST{
 		pos,
 		astFunction,
@@ -304,8 +487,16 @@ func (p *Parser) parseExtern() PrototypeAST {
 	if p.lexer.token == TokUnknown && p.lexer.unknownVal == ' ' {
 		p.lexer.nextToken()
 	}
-	p.knownVars = make(map[string]string)
-	return p.parsePrototype()
+
+	pos := p.lexer.tokStart
+	proto := p.parsePrototype()
+	if proto.ReturnType == "" {
+		proto.ReturnType = LitVoid
+	}
+	if proto.Receiver != "" {
+		p.errorAt(pos, proto.Pos, ErrUnexpectedToken, "Extern functions can't be methods.")
+	}
+	return proto
 }
 
 //func (p *Parser) parseTopLevelExpr() (FunctionAST, error) {
@@ -341,8 +532,26 @@ func (p *Parser) parseExtern() PrototypeAST {
 //	}, nil
 //}
 
+// parseCondition parses the expression before the block of an if or a loop,
+// where 'name {' starts the block rather than a struct literal.
+func (p *Parser) parseCondition() AST {
+	outer := p.noStructLit
+	p.noStructLit = true
+	defer func() { p.noStructLit = outer }()
+	return p.parseExpression()
+}
+
+// parseNested parses an expression between parentheses or brackets, where
+// struct literals are allowed even inside a condition.
+func (p *Parser) parseNested() AST {
+	outer := p.noStructLit
+	p.noStructLit = false
+	defer func() { p.noStructLit = outer }()
+	return p.parseExpression()
+}
+
 func (p *Parser) parseExpression() AST {
-	lhs := p.parseUnary()
+	lhs := p.parseCast()
 	if lhs == nil {
 		return nil
 	}
@@ -369,7 +578,7 @@ func (p *Parser) checkBinOpPrec() (prec, tokenOffset int, ok bool, operator stri
 
 		matched := false
 		for op := range p.binOpPrecedence {
-			if strings.HasPrefix(op, operator + tempOperator) {
+			if strings.HasPrefix(op, operator+tempOperator) {
 				goBack++
 				prevLexers = append(prevLexers, p.lexer.clone())
 				operator += tempOperator
@@ -388,14 +597,13 @@ func (p *Parser) checkBinOpPrec() (prec, tokenOffset int, ok bool, operator stri
 	if !ok {
 		p.lexer = oldLexer
 	} else {
-		p.lexer = prevLexers[len(prevLexers) - goBack]
+		p.lexer = prevLexers[len(prevLexers)-goBack]
 	}
 
 	return prec, goBack, ok, operator
 }
 
 func (p *Parser) parseBinOpRHS(expressionPrec int, lhs AST) AST {
-	pos := p.lexer.pos
 	for {
 		tokenPrec, offset, ok, binop := p.checkBinOpPrec()
 		if !ok {
@@ -405,10 +613,11 @@ func (p *Parser) parseBinOpRHS(expressionPrec int, lhs AST) AST {
 			return lhs
 		}
 
+		pos := p.lexer.tokStart
 		for i := 0; i < offset; i++ {
 			p.lexer.nextToken()
 		}
-		rhs := p.parseUnary()
+		rhs := p.parseCast()
 		if rhs == nil {
 			return nil
 		}
@@ -425,33 +634,74 @@ func (p *Parser) parseBinOpRHS(expressionPrec int, lhs AST) AST {
 		}
 
 		lhs = &BinaryAST{
-			pos,
-			astBinary,
-			binop,
-			lhs,
-			rhs,
+			Pos:  pos,
+			kind: astBinary,
+			Op:   binop,
+			Lhs:  lhs,
+			Rhs:  rhs,
 		}
 	}
 }
 
 func (p *Parser) parsePrimary() AST {
+	return p.parsePostfix(p.parseOperand())
+}
+
+// parsePostfix parses the indexing and field accesses following an operand,
+// as in a[i].x.
+func (p *Parser) parsePostfix(operand AST) AST {
+	for p.lexer.token == TokUnknown && (p.lexer.unknownVal == '[' || p.lexer.unknownVal == '.') {
+		if p.lexer.unknownVal == '.' {
+			p.lexer.nextToken()
+			name := p.lexer.identifier
+			pos := p.checkAndNext(TokIdentifier)
+			if p.lexer.token == TokLParen {
+				args := append([]AST{operand}, p.parseCallArgs(name)...)
+				operand = &CallAST{Pos: pos, kind: astCall, Callee: name, args: args, Method: true}
+				continue
+			}
+			operand = &FieldAST{Pos: pos, kind: astField, Struct: operand, Name: name}
+			continue
+		}
+
+		pos := p.lexer.tokStart
+		p.lexer.nextToken()
+		index := p.parseNested()
+		if p.lexer.token != TokUnknown || p.lexer.unknownVal != ']' {
+			p.syntaxError(ErrUnclosed, "Index is not closed.")
+		}
+		p.lexer.nextToken()
+
+		operand = &IndexAST{Pos: pos, kind: astIndex, Array: operand, Index: index}
+	}
+	return operand
+}
+
+func (p *Parser) parseOperand() AST {
 	switch p.lexer.token {
 	case TokIdentifier:
 		return p.parseIdentifier()
 	case TokStr:
 		return p.parseStr()
+	case TokChar:
+		return p.parseChar()
+	case TokAtom:
+		return p.parseAtom()
 	case TokNumber:
 		return p.parseNumber()
 	case TokLParen:
 		return p.parseParen()
 	case TokTrue, TokFalse:
 		return p.parseBool()
-	default:
-		what := tokens[p.lexer.token]
-		if p.lexer.token == TokUnknown {
-			what = string(p.lexer.unknownVal)
+	case TokMatch:
+		return p.parseMatch()
+	case TokUnknown:
+		if p.lexer.unknownVal == '[' {
+			return p.parseArray()
 		}
-		p.addError("'"+what+"' is not an expression.")
+		fallthrough
+	default:
+		p.syntaxError(ErrUnexpectedToken, "'"+p.lexer.tokenText()+"' is not an expression.")
 		return nil
 	}
 }
@@ -459,46 +709,108 @@ func (p *Parser) parsePrimary() AST {
 func (p *Parser) parseStmt() AST {
 	switch p.lexer.token {
 	case TokIdentifier:
-		return p.parseIdentifier()
+		ident := p.parsePostfix(p.parseIdentifier())
+		variable, ok := ident.(*VariableAST)
+		switch ident.(type) {
+		case *VariableAST, *IndexAST, *FieldAST:
+			if p.lexer.token == TokAssign {
+				return p.parseAssignment(ident)
+			}
+		}
+		if ok && p.lexer.token == TokTypeSpec {
+			// A label, which only loops can have
+			p.lexer.nextToken()
+			if p.lexer.token != TokForLoop {
+				p.syntaxError(ErrUnexpectedToken, "Label '"+variable.Name+"' must be followed by a loop.")
+			}
+			return p.parseLoop(variable.Name)
+		}
+		return ident
+	case TokLet, TokVar:
+		return p.parseVarDecl()
 	case TokIf:
 		return p.parseIfElse()
 	case TokReturn:
 		return p.parseReturn()
 	case TokForLoop:
-		return p.parseLoop()
+		return p.parseLoop("")
+	case TokBreak, TokContinue:
+		return p.parseBranch()
+	case TokMatch:
+		return p.parseMatch()
 	default:
-		what := tokens[p.lexer.token]
-		if p.lexer.token == TokUnknown {
-			what = string(p.lexer.unknownVal)
-		}
-		p.addError("'"+what+"' is not a statement.")
+		p.syntaxError(ErrUnexpectedToken, "'"+p.lexer.tokenText()+"' is not a statement.")
 		return nil
 	}
 }
 
+// parseVarDecl parses 'let name[: type] = value' or the same with 'var'.
+func (p *Parser) parseVarDecl() AST {
+	mutable := p.lexer.token == TokVar
+	p.lexer.nextToken()
+
+	// The colon before the type isn't the start of an atom
+	name := p.lexer.identifier
+	p.lexer.ignoreAtoms = true
+	namePos := p.checkAndNext(TokIdentifier)
+	p.lexer.ignoreAtoms = false
+
+	varType := ""
+	typePos := namePos
+	if p.lexer.token == TokTypeSpec {
+		p.lexer.nextToken()
+		varType, typePos = p.parseType()
+	}
+
+	if p.lexer.token != TokAssign {
+		p.syntaxError(ErrUnexpectedToken, "Variable '"+name+"' must be initialized.")
+	}
+	p.lexer.nextToken()
+
+	return &VarDeclAST{
+		Pos:     namePos,
+		kind:    astVarDecl,
+		Name:    name,
+		Mutable: mutable,
+		VarType: varType,
+		TypePos: typePos,
+		Value:   p.parseExpression(),
+	}
+}
+
+func (p *Parser) parseAssignment(target AST) AST {
+	p.lexer.nextToken()
+	return &AssignAST{
+		Pos:    target.Position(),
+		kind:   astAssign,
+		Target: target,
+		Value:  p.parseExpression(),
+	}
+}
+
 func (p *Parser) parseBool() AST {
-	pos := p.lexer.pos
+	pos := p.lexer.tokStart
 	val := 0
 
 	if p.lexer.identifier == "true" {
 		val = 1
 	} else if p.lexer.identifier != "false" {
-		p.addError("Error occurred while parsing boolean")
+		p.addError(ErrUnexpectedToken, "Error occurred while parsing boolean")
 	}
 
 	p.lexer.nextToken()
-	return &BoolAST{pos, astBool, val}
+	return &BoolAST{Pos: pos, kind: astBool, Value: val}
 }
 
 func (p *Parser) parseParen() AST {
 	p.lexer.nextToken()
-	val := p.parseExpression()
+	val := p.parseNested()
 	if val == nil {
 		return nil
 	}
 
 	if p.lexer.token != TokRParen {
-		p.addError("Parenthesis are not closed.")
+		p.syntaxError(ErrUnclosed, "Parenthesis are not closed.")
 	}
 
 	p.lexer.nextToken()
@@ -506,34 +818,49 @@ func (p *Parser) parseParen() AST {
 }
 
 func (p *Parser) parseIdentifier() AST {
-	pos := p.lexer.pos
+	pos := p.lexer.tokStart
 	name := p.lexer.identifier
 
 	p.lexer.nextToken()
 
-	if p.lexer.token != TokLParen {
-		varType, ok := p.knownVars[name]
-		if !ok {
-			p.addError(fmt.Sprintf(`Variable "%s" does not exist!`, name))
+	if p.lexer.token == TokLBrace && !p.noStructLit {
+		return p.parseStructLit(name, pos)
+	}
+
+	if p.enums[name] && p.lexer.token == TokUnknown && p.lexer.unknownVal == '.' {
+		p.lexer.nextToken()
+		variant := p.lexer.identifier
+		p.checkAndNext(TokIdentifier)
+
+		var args []AST
+		if p.lexer.token == TokLParen {
+			args = p.parseCallArgs(variant)
 		}
+		return &VariantAST{Pos: pos, kind: astVariant, Enum: name, Variant: variant, Args: args}
+	}
 
+	if p.lexer.token != TokLParen {
 		return &VariableAST{
-			Pos: 	  pos,
-			kind:     astVariable,
-			Name:     name,
-			VarType:  varType,
+			Pos:  pos,
+			kind: astVariable,
+			Name: name,
 		}
 	}
 
+	return &CallAST{Pos: pos, kind: astCall, Callee: name, args: p.parseCallArgs(name)}
+}
+
+// parseCallArgs parses the parenthesized arguments of a call to name.
+func (p *Parser) parseCallArgs(name string) []AST {
 	p.lexer.nextToken()
 
 	var args []AST
 	for p.lexer.token != TokRParen {
 		if p.lexer.token == TokEOF {
-			p.addError("Function call is not closed")
+			p.syntaxError(ErrUnclosed, "Function call is not closed")
 		}
 
-		arg := p.parseExpression()
+		arg := p.parseNested()
 		if arg != nil {
 			args = append(args, arg)
 		}
@@ -543,122 +870,370 @@ func (p *Parser) parseIdentifier() AST {
 		}
 
 		if p.lexer.token != TokArgSep {
-			p.addError("Expected ',' in '"+name+"' function call.")
+			p.syntaxError(ErrUnexpectedToken, "Expected ',' in '"+name+"' function call.")
 		}
 
 		p.lexer.nextToken()
 	}
 
 	p.lexer.nextToken()
-	return &CallAST{pos, astCall, name, args}
+	return args
 }
 
 func (p *Parser) parseStr() AST {
-	pos := p.lexer.pos
+	pos := p.lexer.tokStart
 	val := p.lexer.strVal
 
 	p.lexer.nextToken()
-	return &StringAST{pos, astString, val}
+	return &StringAST{Pos: pos, kind: astString, Value: val}
 }
 
-func (p *Parser) parseNumber() AST {
-	pos := p.lexer.pos
-	val := p.lexer.numVal
-	kind := astNumberInt
-	if p.lexer.isFloat {
-		kind = astNumberFloat
-	}
+func (p *Parser) parseAtom() AST {
+	pos := p.lexer.tokStart
+	name := p.lexer.identifier[1:]
 
 	p.lexer.nextToken()
-	return &NumberLiteralAST{pos, kind, val}
+	return &AtomAST{Pos: pos, kind: astAtom, Value: name}
 }
 
-func (p *Parser) parseIfElse() AST {
-	pos := p.lexer.pos
+func (p *Parser) parseArray() AST {
+	pos := p.lexer.tokStart
 	p.lexer.nextToken()
 
-	cond := p.parseExpression()
-	if cond == nil {
-		p.addError("Syntax Error: No condition inside if")
-	}
-	scopePos := p.checkAndNext(TokLBrace)
-
-	var trueBody []AST
-	for p.lexer.token != TokRBrace {
+	var elements []AST
+	for p.lexer.token != TokUnknown || p.lexer.unknownVal != ']' {
 		if p.lexer.token == TokEOF {
-			p.addError("No closing brace in 'if' statement")
+			p.syntaxError(ErrUnclosed, "Array is not closed.")
 		}
 
-		body := p.parseStmt()
-		if body != nil {
-			trueBody = append(trueBody, body)
+		elements = append(elements, p.parseNested())
+		if p.lexer.token == TokUnknown && p.lexer.unknownVal == ']' {
+			break
+		}
+		if p.lexer.token != TokArgSep {
+			p.syntaxError(ErrUnexpectedToken, "Expected ',' or ']' in the array.")
 		}
+		p.lexer.nextToken()
 	}
 
 	p.lexer.nextToken()
+	return &ArrayAST{Pos: pos, kind: astArray, Elements: elements}
+}
 
-	var elseScope Pos
-	var elseBody []AST
-	var elseIfBody []ElseIfAST
-	for {
+// parseStruct parses 'struct Name { field: type, ... }'. The commas between
+// fields can be left out at the end of a line.
+func (p *Parser) parseStruct() AST {
+	p.lexer.nextToken()
+	name := p.lexer.identifier
+	pos := p.checkAndNext(TokIdentifier)
+	p.checkAndNext(TokLBrace)
+
+	var fields []ArgsPrototype
+	for p.lexer.token != TokRBrace {
+		if p.atDeclaration() {
+			p.syntaxError(ErrUnclosed, "Struct '"+name+"' is not closed.")
+		}
+
+		field := ArgsPrototype{Pos: p.lexer.tokStart, Name: p.lexer.identifier}
+		p.fieldName()
 		if p.lexer.token == TokEOF {
-			p.addError("No closing brace in 'else if' statement")
+			p.syntaxError(ErrUnclosed, "Struct '"+name+"' is not closed.")
 		}
+		field.ArgType, field.TypePos = p.parseType()
+		fields = append(fields, field)
 
-		if p.lexer.token != TokElse {
-			break
+		if p.lexer.token == TokArgSep {
+			p.lexer.nextToken()
 		}
+	}
 
-		p.lexer.nextToken()
+	p.lexer.nextToken()
+	return &StructAST{Pos: pos, kind: astStruct, Name: name, Fields: fields}
+}
 
-		if p.lexer.token != TokIf {
-			elseScope = p.checkAndNext(TokLBrace)
-			for p.lexer.token != TokRBrace {
+// parseEnum parses 'enum Name { Variant(T, ...), ... }'. The first pass
+// records the name, so that Name.Variant can be told apart from a field of a
+// variable in every file.
+func (p *Parser) parseEnum() AST {
+	p.lexer.nextToken()
+	name := p.lexer.identifier
+	pos := p.checkAndNext(TokIdentifier)
+	p.checkAndNext(TokLBrace)
+	p.enums[name] = true
+
+	var variants []Variant
+	for p.lexer.token != TokRBrace {
+		if p.atDeclaration() {
+			p.syntaxError(ErrUnclosed, "Enum '"+name+"' is not closed.")
+		}
+
+		variant := Variant{Pos: p.lexer.tokStart, Name: p.lexer.identifier}
+		p.checkAndNext(TokIdentifier)
+		if p.lexer.token == TokLParen {
+			p.lexer.nextToken()
+			for p.lexer.token != TokRParen {
 				if p.lexer.token == TokEOF {
-					p.addError("No closing brace in 'else' statement")
+					p.syntaxError(ErrUnclosed, "Enum '"+name+"' is not closed.")
 				}
-
-				body := p.parseStmt()
-				if body != nil {
-					elseBody = append(elseBody, body)
+				typ, typePos := p.parseType()
+				variant.Payload = append(variant.Payload, typ)
+				variant.PayloadPos = append(variant.PayloadPos, typePos)
+				if p.lexer.token != TokArgSep {
+					break
 				}
+				p.lexer.nextToken()
 			}
+			p.checkAndNext(TokRParen)
+		}
+		variants = append(variants, variant)
 
+		if p.lexer.token == TokArgSep {
 			p.lexer.nextToken()
+		}
+	}
+
+	p.lexer.nextToken()
+	return &EnumAST{Pos: pos, kind: astEnum, Name: name, Variants: variants}
+}
+
+// parseMatch parses 'match value { pattern => body, ... }'. The comma is
+// only optional after a block, since an expression could go on with the
+// next pattern otherwise.
+func (p *Parser) parseMatch() AST {
+	pos := p.checkAndNext(TokMatch)
+	value := p.parseCondition()
+	open := p.checkAndNext(TokLBrace)
+
+	var arms []MatchArm
+	for p.lexer.token != TokRBrace {
+		if p.atDeclaration() {
+			p.syntaxError(ErrUnclosed, "Match is not closed.", fmt.Sprintf("it was opened at %d:%d", open.row, open.col))
+		}
+
+		arm := p.parsePattern()
+		p.checkAndNext(TokArrow)
+		if p.lexer.token == TokLBrace {
+			body := p.parseBlock("Match arm")
+			arm.Block = &body
+		} else {
+			arm.Value = p.parseNested()
+			if p.lexer.token != TokArgSep && p.lexer.token != TokRBrace {
+				p.syntaxError(ErrUnexpectedToken, "Expected ',' after the match arm.")
+			}
+		}
+		arms = append(arms, arm)
+
+		if p.lexer.token == TokArgSep {
+			p.lexer.nextToken()
+		}
+	}
+
+	p.lexer.nextToken()
+	return &MatchAST{Pos: pos, kind: astMatch, Value: value, Arms: arms}
+}
+
+// parsePattern parses the pattern of a match arm: '_', Enum.Variant with the
+// names for its payload in parentheses, or a literal.
+func (p *Parser) parsePattern() MatchArm {
+	arm := MatchArm{Pos: p.lexer.tokStart}
+	switch {
+	case p.lexer.token == TokIdentifier && p.lexer.identifier == "_":
+		p.lexer.nextToken()
+	case p.lexer.token == TokIdentifier && p.enums[p.lexer.identifier]:
+		arm.Enum = p.lexer.identifier
+		p.lexer.nextToken()
+		if p.lexer.token != TokUnknown || p.lexer.unknownVal != '.' {
+			p.syntaxError(ErrUnexpectedToken, "Expected '.' and a variant of "+arm.Enum+".")
+		}
+		p.lexer.nextToken()
+		arm.Variant = p.lexer.identifier
+		p.checkAndNext(TokIdentifier)
+
+		if p.lexer.token == TokLParen {
+			p.lexer.nextToken()
+			for p.lexer.token != TokRParen {
+				name := p.lexer.identifier
+				pos := p.checkAndNext(TokIdentifier)
+				arm.Bindings = append(arm.Bindings, &VariableAST{Pos: pos, kind: astVariable, Name: name})
+				if p.lexer.token != TokArgSep {
+					break
+				}
+				p.lexer.nextToken()
+			}
+			p.checkAndNext(TokRParen)
+		}
+	case p.lexer.token == TokNumber:
+		arm.Literal = p.parseNumber()
+	case p.lexer.token == TokStr:
+		arm.Literal = p.parseStr()
+	case p.lexer.token == TokAtom:
+		arm.Literal = p.parseAtom()
+	case p.lexer.token == TokUnknown && p.lexer.unknownVal == '-':
+		p.lexer.nextToken()
+		if p.lexer.token != TokNumber {
+			p.syntaxError(ErrUnexpectedToken, "Expected a number after '-' in the pattern.")
+		}
+		number := p.parseNumber().(*NumberLiteralAST)
+		number.Pos = arm.Pos
+		number.Value = -number.Value
+		arm.Literal = number
+	case p.lexer.token == TokIdentifier:
+		p.syntaxError(ErrUnexpectedToken, "'"+p.lexer.identifier+"' is not an enum, patterns can only start with one.")
+	default:
+		p.syntaxError(ErrUnexpectedToken, "'"+p.lexer.tokenText()+"' is not a pattern.")
+	}
+	return arm
+}
+
+// fieldName skips 'name:' starting a field, so that what follows the colon
+// isn't read as an atom.
+func (p *Parser) fieldName() {
+	p.lexer.ignoreAtoms = true
+	p.checkAndNext(TokIdentifier)
+	p.lexer.ignoreAtoms = false
+	p.checkAndNext(TokTypeSpec)
+}
+
+// parseStructLit parses the '{ field: value, ... }' after the name of a
+// struct.
+func (p *Parser) parseStructLit(name string, pos Pos) AST {
+	p.lexer.nextToken()
+
+	var fields []FieldInit
+	for p.lexer.token != TokRBrace {
+		field := FieldInit{Pos: p.lexer.tokStart, Name: p.lexer.identifier}
+		p.fieldName()
+		field.Value = p.parseNested()
+		fields = append(fields, field)
+
+		if p.lexer.token != TokArgSep {
 			break
 		}
+		p.lexer.nextToken()
+	}
+
+	if p.lexer.token != TokRBrace {
+		p.syntaxError(ErrUnclosed, "Struct literal is not closed.")
+	}
+	p.lexer.nextToken()
+	return &StructLitAST{Pos: pos, kind: astStructLit, Name: name, Fields: fields}
+}
 
+// parseType parses a type name, '[T; n]' or '[]T', and returns its spelling.
+func (p *Parser) parseType() (string, Pos) {
+	pos := p.lexer.tokStart
+	if p.lexer.token == TokIdentifier {
+		name := p.lexer.identifier
 		p.lexer.nextToken()
+		return name, pos
+	}
 
-		elseIfCond := p.parseExpression()
-		if elseIfCond == nil {
-			p.addError("No condition inside 'else if'")
+	if p.lexer.token != TokUnknown || p.lexer.unknownVal != '[' {
+		p.syntaxError(ErrUnexpectedToken, "Expected a type.")
+	}
+	p.lexer.nextToken()
+
+	// A type cut off by the end of the file isn't closed, whichever part of
+	// it is missing
+	unterminated := func() {
+		if p.lexer.token == TokEOF {
+			p.syntaxError(ErrUnclosed, "Array type is not closed.", fmt.Sprintf("it was opened at %d:%d", pos.row, pos.col))
 		}
+	}
+	unterminated()
 
-		elseIfScope := p.checkAndNext(TokLBrace)
+	if p.lexer.token == TokUnknown && p.lexer.unknownVal == ']' {
+		p.lexer.nextToken()
+		elem, _ := p.parseType()
+		return sliceType(elem), pos
+	}
 
-		var tempBody []AST
-		for p.lexer.token != TokRBrace {
-			if p.lexer.token == TokEOF {
-				p.addError("No closing brace in 'else if' statement")
-			}
+	elem, _ := p.parseType()
+	unterminated()
+	if p.lexer.token != TokUnknown || p.lexer.unknownVal != ';' {
+		p.syntaxError(ErrUnexpectedToken, "Expected ';' and the length of the array.")
+	}
+	p.lexer.nextToken()
+	unterminated()
 
-			body := p.parseStmt()
-			if body != nil {
-				tempBody = append(tempBody, body)
-			}
+	length := p.lexer.numVal
+	if p.lexer.token != TokNumber || p.lexer.isFloat || length < 0 {
+		p.syntaxError(ErrUnexpectedToken, "Length of the array must be a number.")
+	}
+	p.lexer.nextToken()
+	unterminated()
+
+	if p.lexer.token != TokUnknown || p.lexer.unknownVal != ']' {
+		p.syntaxError(ErrUnclosed, "Array type is not closed.")
+	}
+	p.lexer.nextToken()
+	return arrayType(elem, int(length)), pos
+}
+
+func (p *Parser) parseChar() AST {
+	pos := p.lexer.tokStart
+	val := p.lexer.strVal
+	if len(val) != 1 {
+		p.addError(ErrUnexpectedToken, "Character literal must hold exactly one byte.")
+		val = "\x00"
+	}
+
+	p.lexer.nextToken()
+	return &CharAST{Pos: pos, kind: astChar, Value: val[0]}
+}
+
+func (p *Parser) parseNumber() AST {
+	pos := p.lexer.tokStart
+	val := p.lexer.numVal
+	kind := astNumberInt
+	if p.lexer.isFloat {
+		kind = astNumberFloat
+	}
+	if p.lexer.extraDot {
+		p.addError(ErrUnexpectedToken, "Invalid use of '.' in a number.", "a number has at most one '.'")
+	}
+
+	p.lexer.nextToken()
+	return &NumberLiteralAST{Pos: pos, kind: kind, Value: val}
+}
+
+func (p *Parser) parseIfElse() AST {
+	pos := p.lexer.tokStart
+	p.lexer.nextToken()
+
+	cond := p.parseCondition()
+	if cond == nil {
+		p.syntaxError(ErrUnexpectedToken, "No condition inside if")
+	}
+	trueBody := p.parseBlock("'if' statement")
+
+	var elseBody BlockAST
+	var elseIfBody []ElseIfAST
+	for {
+		if p.lexer.token != TokElse {
+			break
 		}
 
 		p.lexer.nextToken()
+
+		if p.lexer.token != TokIf {
+			elseBody = p.parseBlock("'else' statement")
+			break
+		}
+
+		p.lexer.nextToken()
+
+		elseIfCond := p.parseCondition()
+		if elseIfCond == nil {
+			p.syntaxError(ErrUnexpectedToken, "No condition inside 'else if'")
+		}
+
+		elseIfBlock := p.parseBlock("'else if' statement")
 		elseIfBody = append(elseIfBody, ElseIfAST{
-			elseIfScope,
+			elseIfBlock.Pos,
 			astIfElse,
 			elseIfCond,
-			BlockAST{
-				elseIfScope,
-				astBlock,
-				tempBody,
-			},
+			elseIfBlock,
 		})
 	}
 
@@ -666,22 +1241,14 @@ func (p *Parser) parseIfElse() AST {
 		pos,
 		astIfElse,
 		cond,
-		BlockAST{
-			scopePos,
-			astBlock,
-			trueBody,
-		},
-		BlockAST{
-			elseScope,
-			astBlock,
-			elseBody,
-		},
+		trueBody,
+		elseBody,
 		elseIfBody,
 	}
 }
 
 func (p *Parser) parseReturn() AST {
-	pos := p.lexer.pos
+	pos := p.lexer.tokStart
 	p.lexer.ignoreNewLine = false
 	p.lexer.nextToken()
 	p.lexer.ignoreNewLine = true
@@ -692,6 +1259,7 @@ func (p *Parser) parseReturn() AST {
 			pos,
 			astReturn,
 			nil,
+			false,
 		}
 	}
 
@@ -700,39 +1268,53 @@ func (p *Parser) parseReturn() AST {
 		pos,
 		astReturn,
 		value,
+		false,
 	}
 }
 
-func (p *Parser) parseLoopBody() []AST {
-	var body []AST
-	for p.lexer.token != TokRBrace {
-		if p.lexer.token == TokEOF {
-			p.addError("For loop has no end")
-		}
+// parseBranch parses 'break' or 'continue' with an optional label on the same
+// line.
+func (p *Parser) parseBranch() AST {
+	pos := p.lexer.tokStart
+	k := astBreak
+	if p.lexer.token == TokContinue {
+		k = astContinue
+	}
 
-		expr := p.parseStmt()
-		if expr != nil {
-			body = append(body, expr)
-		}
+	p.lexer.ignoreNewLine = false
+	p.lexer.nextToken()
+	p.lexer.ignoreNewLine = true
+
+	label := ""
+	if p.lexer.token == TokIdentifier {
+		label = p.lexer.identifier
+		p.lexer.nextToken()
+	} else if p.lexer.token == TokUnknown && (p.lexer.unknownVal == 10 || p.lexer.unknownVal == 13) {
+		p.lexer.nextToken()
 	}
 
-	return body
+	return &BranchAST{Pos: pos, kind: k, Label: label}
 }
 
-func (p *Parser) parseLoop() AST {
-	pos := p.lexer.pos
+func (p *Parser) parseLoop(label string) AST {
+	pos := p.lexer.tokStart
 	p.lexer.nextToken()
 
-	if p.lexer.token == TokTrue || p.lexer.token == TokFalse {
-		cond := p.parseExpression()
+	// Anything but 'index, element in' or 'element in' is a loop condition
+	start := p.lexer.clone()
+	ind := p.lexer.identifier
+	if p.lexer.token == TokIdentifier {
+		p.lexer.nextToken()
+	}
+
+	if p.lexer.token != TokArgSep && p.lexer.token != TokIn {
+		p.lexer = start
+		cond := p.parseCondition()
 		if cond == nil {
-			panic("No condition in the loop")
+			p.syntaxError(ErrUnexpectedToken, "No condition in the loop")
 		}
 
-		blockPos := p.checkAndNext(TokLBrace)
-		body := p.parseLoopBody()
-		p.lexer.nextToken()
-
+		body := p.parseBlock("For loop")
 		return &LoopAST{
 			pos,
 			astLoop,
@@ -740,83 +1322,41 @@ func (p *Parser) parseLoop() AST {
 			cond,
 			"",
 			"",
-			BlockAST{
-				blockPos,
-				astBlock,
-				body,
-			},
+			body,
+			label,
 		}
 	}
 
-	ind := p.lexer.identifier
-	varPos := p.checkAndNext(TokIdentifier)
-
-	if p.lexer.token != TokArgSep {
-		blockPos := p.checkAndNext(TokLBrace)
-
-		body := p.parseLoopBody()
+	element := ind
+	if start.token != TokIdentifier {
+		p.syntaxError(ErrUnexpectedToken, "No variable in the loop")
+	}
+	if p.lexer.token == TokArgSep {
 		p.lexer.nextToken()
-
-		return &LoopAST{
-			pos,
-			astLoop,
-			false,
-			&VariableAST{
-				Pos: 	  varPos,
-				kind:     astVariable,
-				Name:     ind,
-				VarType:  LitBool,
-			},
-			"",
-			"",
-			BlockAST{
-				blockPos,
-				astBlock,
-				body,
-			},
+		if p.lexer.token != TokIdentifier {
+			p.syntaxError(ErrUnexpectedToken, "No variable in the loop")
 		}
-	}
 
-	p.lexer.nextToken()
-	if p.lexer.token != TokIdentifier {
-		panic("No variable in the loop")
+		element = p.lexer.identifier
+		p.lexer.nextToken()
+	} else {
+		ind = ""
 	}
 
-	element := p.lexer.identifier
-	p.lexer.nextToken()
 	if p.lexer.token != TokIn {
-		panic("No `in` keyword in the loop")
+		p.syntaxError(ErrUnexpectedToken, "No `in` keyword in the loop")
 	}
 
 	p.lexer.nextToken()
-	cond := p.parseExpression()
+	cond := p.parseCondition()
 	if cond == nil {
-		panic("No condition after 'in' keyword")
+		p.syntaxError(ErrUnexpectedToken, "No condition after 'in' keyword")
 	}
-
-	// Shadowing variables
-	oldInd, okInd := p.knownVars[ind]
-	oldElement, okElem := p.knownVars[element]
-
-	p.knownVars[ind] = LitInt
-	p.knownVars[element] = ASTTypeToLit(cond.Kind())
-
-	blockPos := p.checkAndNext(TokLBrace)
-	body := p.parseLoopBody()
-	if okInd {
-		p.knownVars[ind] = oldInd
-	} else {
-		delete(p.knownVars, p.knownVars[ind])
+	if p.lexer.token == TokRange || p.lexer.token == TokRangeInclusive {
+		cond = p.parseRange(cond)
 	}
 
-	if okElem {
-		p.knownVars[ind] = oldElement
-	} else {
-		delete(p.knownVars, p.knownVars[ind])
-	}
-
-	p.lexer.nextToken()
-
+	body := p.parseBlock("For loop")
 	return &LoopAST{
 		pos,
 		astLoop,
@@ -824,17 +1364,40 @@ func (p *Parser) parseLoop() AST {
 		cond,
 		ind,
 		element,
-		BlockAST{
-			blockPos,
-			astBlock,
-			body,
-		},
+		body,
+		label,
+	}
+}
+
+// parseRange parses the rest of 'start..end [step n]' or 'start..=end [step n]'.
+// 'step' is only a keyword there, so it stays usable as a name.
+func (p *Parser) parseRange(start AST) AST {
+	r := &RangeAST{
+		Pos:       p.lexer.tokStart,
+		kind:      astRange,
+		Start:     start,
+		Inclusive: p.lexer.token == TokRangeInclusive,
+	}
+
+	p.lexer.nextToken()
+	r.End = p.parseCondition()
+	if r.End == nil {
+		p.syntaxError(ErrUnexpectedToken, "No end of the range")
+	}
+
+	if p.lexer.token == TokIdentifier && p.lexer.identifier == "step" {
+		p.lexer.nextToken()
+		r.Step = p.parseCondition()
+		if r.Step == nil {
+			p.syntaxError(ErrUnexpectedToken, "No step of the range")
+		}
 	}
+	return r
 }
 
-func (p *Parser) parseAssign(panicMessage string) interface{} {
+func (p *Parser) parseAssign(errMessage string) interface{} {
 	if p.lexer.token != TokAssign {
-		panic(panicMessage)
+		p.syntaxError(ErrAttribute, errMessage)
 	}
 
 	p.lexer.nextToken()
@@ -846,7 +1409,8 @@ func (p *Parser) parseAssign(panicMessage string) interface{} {
 		return p.lexer.numVal
 	}
 
-	panic(panicMessage)
+	p.syntaxError(ErrAttribute, errMessage)
+	return nil
 }
 
 func (p *Parser) parsePrimitiveAttr() {
@@ -857,18 +1421,18 @@ func (p *Parser) parsePrimitiveAttr() {
 	for prevToken != TokRParen {
 		if p.lexer.token == TokRParen {
 			if prevToken == TokArgSep {
-				p.addError("Wrong attribute definition. Excepted: ','")
+				p.syntaxError(ErrAttribute, "Wrong attribute definition. Excepted: ','")
 			}
 
 			break
 		}
 
 		if p.lexer.token == TokEOF {
-			p.addError("Primitive attribute is not closed")
+			p.syntaxError(ErrUnclosed, "Primitive attribute is not closed")
 		}
 
 		if p.lexer.token != TokIdentifier {
-			p.addError("No identifier in the primitive attribute")
+			p.syntaxError(ErrAttribute, "No identifier in the primitive attribute")
 		}
 
 		switch p.lexer.identifier {
@@ -881,23 +1445,23 @@ func (p *Parser) parsePrimitiveAttr() {
 			case ":binary":
 				p.isBinaryOp = true
 			default:
-				p.addError("Type '" + typ.(string) + "' in the primitive attribute does not exist")
+				p.addError(ErrAttribute, fmt.Sprintf("Type '%v' in the primitive attribute does not exist", typ))
 			}
 		case "precedence":
 			p.lexer.nextToken()
 			precedence, ok := p.parseAssign("Invalid value assigning in the 'precedence' option of the primitive attribute").(float64)
 			if !ok {
-				p.addError("Could not assign value to precedence because value is not a number")
+				p.addError(ErrAttribute, "Could not assign value to precedence because value is not a number")
 			}
 
 			p.defaultPrecedence = int(precedence)
 		default:
-			p.addError("There is no '" + p.lexer.identifier + "' option in the primitive attribute")
+			p.syntaxError(ErrAttribute, "There is no '"+p.lexer.identifier+"' option in the primitive attribute")
 		}
 
 		p.lexer.nextToken()
 		if p.lexer.token != TokArgSep && p.lexer.token != TokRParen {
-			p.addError("Wrong attribute definition. Expected ',' or ')'.")
+			p.syntaxError(ErrAttribute, "Wrong attribute definition. Expected ',' or ')'.")
 		}
 
 		prevToken = p.lexer.token
@@ -911,31 +1475,43 @@ func (p *Parser) parseAttribute() {
 	p.lexer.nextToken()
 	for p.lexer.unknownVal != ']' {
 		if p.lexer.token == TokEOF {
-			p.addError("Attribute is not closed")
+			p.syntaxError(ErrUnclosed, "Attribute is not closed")
 		}
 
 		if p.lexer.token != TokIdentifier {
-			p.addError("Syntax Error: No identifier in the attribute")
+			p.syntaxError(ErrAttribute, "No identifier in the attribute")
 		}
 
 		switch p.lexer.identifier {
 		case "primitive":
 			p.parsePrimitiveAttr()
 		default:
-			p.addError("Attribute Error: '" + p.lexer.identifier + "' does not exist")
+			p.syntaxError(ErrAttribute, "Attribute '"+p.lexer.identifier+"' does not exist")
 		}
 
 		if p.lexer.token != TokArgSep && p.lexer.unknownVal != ']' {
-			p.addError("Wrong attribute definition. Expected: ',' or ']'")
+			p.syntaxError(ErrAttribute, "Wrong attribute definition. Expected: ',' or ']'")
 		}
 	}
 
 	p.lexer.nextToken()
 }
 
+// parseCast parses a unary expression followed by any number of 'as type'.
+// Casts bind tighter than binary operators, so 'a + b as i64' only casts b.
+func (p *Parser) parseCast() AST {
+	value := p.parseUnary()
+	for value != nil && p.lexer.token == TokAs {
+		p.lexer.nextToken()
+		to, toPos := p.parseType()
+		value = &CastAST{Pos: value.Position(), kind: astCast, Value: value, To: to, ToPos: toPos}
+	}
+	return value
+}
+
 func (p *Parser) parseUnary() AST {
-	pos := p.lexer.pos
-	if p.lexer.token != TokUnknown || p.lexer.unknownVal == ' ' {
+	pos := p.lexer.tokStart
+	if p.lexer.token != TokUnknown || p.lexer.unknownVal == ' ' || p.lexer.unknownVal == '[' {
 		return p.parsePrimary()
 	}
 
@@ -943,7 +1519,7 @@ func (p *Parser) parseUnary() AST {
 	p.lexer.nextToken()
 	if op := p.parseUnary(); op != nil {
 		return &UnaryAST{
-			Pos: 	  pos,
+			Pos:      pos,
 			kind:     astUnary,
 			Operator: int(unaryOp),
 			Operand:  op,
@@ -951,4 +1527,4 @@ func (p *Parser) parseUnary() AST {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -1,6 +1,3 @@
 #!/bin/bash
-rm example.s
-go run . 2>&1 |cat >example.ll
-llc -relocation-model=pic example.ll
-clang example.s
-./a.out
+go run -tags llvm . emit -o example.ll test.nv
+go run -tags llvm . run test.nv
@@ -0,0 +1,142 @@
+//go:build llvm
+// +build llvm
+
+package main
+
+import (
+	"novum-lang/llvm/bindings/go/llvm"
+	"os"
+)
+
+// Runtime helpers are generated into the module the first time a program
+// needs them. They are built on the C library, which every executable is
+// linked with anyway.
+
+const strConcatName = "novum.strconcat"
+
+// intrinsicCodegen generates the calls to the intrinsics.
+var intrinsicCodegen = map[string]func(args []llvm.Value) llvm.Value{
+	"identical": func(args []llvm.Value) llvm.Value {
+		return builder.CreateICmp(llvm.IntEQ, args[0], args[1], "identicaltmp")
+	},
+	"len": func(args []llvm.Value) llvm.Value {
+		if args[0].Type().TypeKind() == llvm.PointerTypeKind {
+			strlen := libcFunction("strlen", sizeType(), args[0].Type())
+			length := builder.CreateCall(strlen, []llvm.Value{args[0]}, "strlen")
+			return builder.CreateTrunc(length, ctx.Int32Type(), "lentmp")
+		}
+		return builder.CreateExtractValue(args[0], 1, "lentmp")
+	},
+	// str copies the char into a new buffer of two bytes, the second one
+	// ending the string
+	"str": func(args []llvm.Value) llvm.Value {
+		str := llvm.PointerType(ctx.Int8Type(), 0)
+		size := sizeType()
+		malloc := libcFunction("malloc", str, size)
+		buffer := builder.CreateCall(malloc, []llvm.Value{llvm.ConstInt(size, 2, false)}, "strtmp")
+		builder.CreateStore(args[0], buffer)
+		end := builder.CreateInBoundsGEP(buffer, []llvm.Value{llvm.ConstInt(size, 1, false)}, "strend")
+		builder.CreateStore(llvm.ConstInt(ctx.Int8Type(), 0, false), end)
+		return buffer
+	},
+}
+
+// sizeType returns C's size_t, an integer as wide as the pointers of the
+// module's target.
+func sizeType() llvm.Type {
+	targetData := llvm.NewTargetData(module.DataLayout())
+	defer targetData.Dispose()
+	return ctx.IntType(targetData.PointerSize() * 8)
+}
+
+// libcFunction returns the declaration of a C library function, adding it to
+// the module if the program didn't declare it.
+func libcFunction(name string, ret llvm.Type, params ...llvm.Type) llvm.Value {
+	fc := module.NamedFunction(name)
+	if fc.IsNil() {
+		fc = llvm.AddFunction(module, name, llvm.FunctionType(ret, params, false))
+	}
+	return fc
+}
+
+// strConcatFunction returns the function implementing '+' for strings:
+//
+//	novum.strconcat(a, b) {
+//		p = malloc(strlen(a) + strlen(b) + 1)
+//		memcpy(p, a, strlen(a))
+//		memcpy(p + strlen(a), b, strlen(b) + 1)
+//		return p
+//	}
+//
+// The result is a new buffer the program owns. Nothing frees it, see the
+// README for why.
+func strConcatFunction() llvm.Value {
+	if fc := module.NamedFunction(strConcatName); !fc.IsNil() {
+		return fc
+	}
+
+	str := llvm.PointerType(ctx.Int8Type(), 0)
+	size := sizeType()
+	strlen := libcFunction("strlen", size, str)
+	malloc := libcFunction("malloc", str, size)
+	memcpy := libcFunction("memcpy", str, str, str, size)
+
+	fc := llvm.AddFunction(module, strConcatName, llvm.FunctionType(str, []llvm.Type{str, str}, false))
+	fc.SetLinkage(llvm.InternalLinkage)
+	a, b := fc.Param(0), fc.Param(1)
+	a.SetName("a")
+	b.SetName("b")
+
+	// The helper is usually created in the middle of another function
+	outer := builder.GetInsertBlock()
+	defer builder.SetInsertPointAtEnd(outer)
+
+	builder.SetInsertPointAtEnd(ctx.AddBasicBlock(fc, "entry"))
+	lenA := builder.CreateCall(strlen, []llvm.Value{a}, "lena")
+	lenB := builder.CreateCall(strlen, []llvm.Value{b}, "lenb")
+	lenB = builder.CreateAdd(lenB, llvm.ConstInt(size, 1, false), "lenbnull")
+	total := builder.CreateAdd(lenA, lenB, "size")
+
+	buffer := builder.CreateCall(malloc, []llvm.Value{total}, "buffer")
+	builder.CreateCall(memcpy, []llvm.Value{buffer, a, lenA}, "")
+	tail := builder.CreateInBoundsGEP(buffer, []llvm.Value{lenA}, "tail")
+	builder.CreateCall(memcpy, []llvm.Value{tail, b, lenB}, "")
+	builder.CreateRet(buffer)
+
+	return fc
+}
+
+// checkDivisor stops the program when the divisor is zero, like the
+// interpreter does. PRELUDE=empty turns the check off for floats, which then
+// follow IEEE 754.
+func checkDivisor(divisor llvm.Value, kind string) {
+	var isZero llvm.Value
+	if !numberTypes[kind].float {
+		isZero = builder.CreateICmp(llvm.IntEQ, divisor, llvm.ConstInt(llvmType(kind), 0, false), "iszero")
+	} else {
+		if os.Getenv("PRELUDE") == "empty" {
+			return
+		}
+		isZero = builder.CreateFCmp(llvm.FloatOEQ, divisor, llvm.ConstFloat(llvmType(kind), 0), "iszero")
+	}
+
+	panicIf(isZero, "right side of the equation is equal to 0")
+}
+
+// panicIf stops the program with the message when cond is true.
+func panicIf(cond llvm.Value, message string) {
+	fc := builder.GetInsertBlock().Parent()
+	panicBlock := ctx.AddBasicBlock(fc, "panic")
+	okBlock := ctx.AddBasicBlock(fc, "ok")
+	builder.CreateCondBr(cond, panicBlock, okBlock)
+
+	builder.SetInsertPointAtEnd(panicBlock)
+	str := llvm.PointerType(ctx.Int8Type(), 0)
+	puts := libcFunction("puts", ctx.Int32Type(), str)
+	builder.CreateCall(puts, []llvm.Value{builder.CreateGlobalStringPtr("Panic: "+message, "")}, "")
+	exit := libcFunction("exit", ctx.VoidType(), ctx.Int32Type())
+	builder.CreateCall(exit, []llvm.Value{llvm.ConstInt(ctx.Int32Type(), 0, false)}, "")
+	builder.CreateUnreachable()
+
+	builder.SetInsertPointAtEnd(okBlock)
+}
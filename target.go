@@ -0,0 +1,105 @@
+//go:build llvm
+// +build llvm
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"novum-lang/llvm/bindings/go/llvm"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+var targetMachine llvm.TargetMachine
+
+var codeGenLevels = []llvm.CodeGenOptLevel{
+	llvm.CodeGenLevelNone,
+	llvm.CodeGenLevelLess,
+	llvm.CodeGenLevelDefault,
+	llvm.CodeGenLevelAggressive,
+}
+
+// initTarget creates the target machine for the given triple (the host when
+// empty) and stamps its triple and data layout on the module.
+func initTarget(triple string, level int) error {
+	llvm.InitializeAllTargetInfos()
+	llvm.InitializeAllTargets()
+	llvm.InitializeAllTargetMCs()
+	llvm.InitializeAllAsmParsers()
+	llvm.InitializeAllAsmPrinters()
+
+	if triple == "" {
+		triple = llvm.DefaultTargetTriple()
+	}
+
+	target, err := llvm.GetTargetFromTriple(triple)
+	if err != nil {
+		return fmt.Errorf("unknown target '%s': %v", triple, err)
+	}
+
+	// PIC keeps the objects linkable into the position independent
+	// executables most toolchains produce by default.
+	targetMachine = target.CreateTargetMachine(triple, "generic", "", codeGenLevels[level], llvm.RelocPIC, llvm.CodeModelDefault)
+
+	targetData := targetMachine.CreateTargetData()
+	defer targetData.Dispose()
+
+	module.SetTarget(triple)
+	module.SetDataLayout(targetData.String())
+	return nil
+}
+
+// emitNative writes the module as an assembly file or an object file.
+func emitNative(kind string, path string) error {
+	fileType := llvm.ObjectFile
+	if kind == EmitASM {
+		fileType = llvm.AssemblyFile
+	}
+
+	buffer, err := targetMachine.EmitToMemoryBuffer(module, fileType)
+	if err != nil {
+		return err
+	}
+	defer buffer.Dispose()
+
+	if path == "-" {
+		if kind != EmitASM {
+			return errors.New("object files can't be written to stdout")
+		}
+		_, err = os.Stdout.Write(buffer.Bytes())
+		return err
+	}
+
+	return ioutil.WriteFile(path, buffer.Bytes(), 0644)
+}
+
+// buildExecutable emits an object file and links it with the C runtime using
+// the system compiler driver, "cc" unless the CC variable says otherwise.
+func buildExecutable(path string) error {
+	tmpDir, err := ioutil.TempDir("", "novum")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	objPath := filepath.Join(tmpDir, "module.o")
+	if err := emitNative(EmitObj, objPath); err != nil {
+		return err
+	}
+
+	linker := os.Getenv("CC")
+	if linker == "" {
+		linker = "cc"
+	}
+
+	cmd := exec.Command(linker, "-o", path, objPath, "-lm")
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("linking with %s failed: %v", linker, err)
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@0 = private unnamed_addr constant [45 x i8] c"Panic: index out of range at arrays.nv:14:15\00", align 1
+@strtmp = private unnamed_addr constant [8 x i8] c"sum %d\0A\00", align 1
+@strtmp.1 = private unnamed_addr constant [8 x i8] c"len %d\0A\00", align 1
+@strtmp.2 = private unnamed_addr constant [10 x i8] c"third %d\0A\00", align 1
+@1 = private unnamed_addr constant [45 x i8] c"Panic: index out of range at arrays.nv:23:32\00", align 1
+@2 = private unnamed_addr constant [44 x i8] c"Panic: index out of range at arrays.nv:26:9\00", align 1
+@3 = private unnamed_addr constant [45 x i8] c"Panic: index out of range at arrays.nv:26:12\00", align 1
+@strtmp.3 = private unnamed_addr constant [7 x i8] c"row %d\00", align 1
+@strtmp.4 = private unnamed_addr constant [9 x i8] c" has %d\0A\00", align 1
+@strtmp.5 = private unnamed_addr constant [16 x i8] c"last square %d\0A\00", align 1
+@4 = private unnamed_addr constant [45 x i8] c"Panic: index out of range at arrays.nv:33:34\00", align 1
+@5 = private unnamed_addr constant [45 x i8] c"Panic: index out of range at arrays.nv:36:11\00", align 1
+@strtmp.6 = private unnamed_addr constant [11 x i8] c"shared %d\0A\00", align 1
+@6 = private unnamed_addr constant [45 x i8] c"Panic: index out of range at arrays.nv:37:29\00", align 1
+@strtmp.7 = private unnamed_addr constant [10 x i8] c"chars %d\0A\00", align 1
+@strtmp.8 = private unnamed_addr constant [6 x i8] c"hello\00", align 1
+@strtmp.9 = private unnamed_addr constant [17 x i8] c"out of range %d\0A\00", align 1
+@7 = private unnamed_addr constant [45 x i8] c"Panic: index out of range at arrays.nv:40:39\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define i32 @sum({ i32*, i32 } %values) {
+entry:
+  %v = alloca i32, align 4
+  %ind = alloca i32, align 4
+  %total = alloca i32, align 4
+  %values1 = alloca { i32*, i32 }, align 8
+  store { i32*, i32 } %values, { i32*, i32 }* %values1, align 8
+  store i32 0, i32* %total, align 4
+  store i32 0, i32* %ind, align 4
+  %values2 = load { i32*, i32 }, { i32*, i32 }* %values1, align 8
+  %elements = extractvalue { i32*, i32 } %values2, 0
+  %len = extractvalue { i32*, i32 } %values2, 1
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %ind3 = load i32, i32* %ind, align 4
+  %loopcond = icmp slt i32 %ind3, %len
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %0 = getelementptr inbounds i32, i32* %elements, i32 %ind3
+  %elem = load i32, i32* %0, align 4
+  store i32 %elem, i32* %v, align 4
+  %total4 = load i32, i32* %total, align 4
+  %v5 = load i32, i32* %v, align 4
+  %addtmp = add i32 %total4, %v5
+  store i32 %addtmp, i32* %total, align 4
+  br label %looplatch
+
+looplatch:                                        ; preds = %loop
+  %ind6 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind6, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  %total7 = load i32, i32* %total, align 4
+  ret i32 %total7
+}
+
+define { i32*, i32 } @squares() {
+entry:
+  %i = alloca i32, align 4
+  %ind = alloca i32, align 4
+  %result = alloca { i32*, i32 }, align 8
+  %0 = call i8* @malloc(i64 16)
+  %elements = bitcast i8* %0 to i32*
+  %1 = getelementptr inbounds i32, i32* %elements, i32 0
+  store i32 0, i32* %1, align 4
+  %2 = getelementptr inbounds i32, i32* %elements, i32 1
+  store i32 0, i32* %2, align 4
+  %3 = getelementptr inbounds i32, i32* %elements, i32 2
+  store i32 0, i32* %3, align 4
+  %4 = getelementptr inbounds i32, i32* %elements, i32 3
+  store i32 0, i32* %4, align 4
+  %5 = insertvalue { i32*, i32 } undef, i32* %elements, 0
+  %array = insertvalue { i32*, i32 } %5, i32 4, 1
+  store { i32*, i32 } %array, { i32*, i32 }* %result, align 8
+  store i32 0, i32* %ind, align 4
+  store i32 0, i32* %i, align 4
+  %result1 = load { i32*, i32 }, { i32*, i32 }* %result, align 8
+  %lentmp = extractvalue { i32*, i32 } %result1, 1
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %i2 = load i32, i32* %i, align 4
+  %beforeend = icmp slt i32 %i2, %lentmp
+  %afterend = icmp sgt i32 %i2, %lentmp
+  %loopcond = select i1 true, i1 %beforeend, i1 %afterend
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %i3 = load i32, i32* %i, align 4
+  %i4 = load i32, i32* %i, align 4
+  %multmp = mul i32 %i3, %i4
+  %result5 = load { i32*, i32 }, { i32*, i32 }* %result, align 8
+  %i6 = load i32, i32* %i, align 4
+  %len = extractvalue { i32*, i32 } %result5, 1
+  %outofrange = icmp uge i32 %i6, %len
+  br i1 %outofrange, label %panic, label %ok
+
+panic:                                            ; preds = %loop
+  %6 = call i32 @puts(i8* getelementptr inbounds ([45 x i8], [45 x i8]* @0, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %loop
+  %elements7 = extractvalue { i32*, i32 } %result5, 0
+  %7 = getelementptr inbounds i32, i32* %elements7, i32 %i6
+  store i32 %multmp, i32* %7, align 4
+  br label %looplatch
+
+looplatch:                                        ; preds = %ok
+  %i8 = load i32, i32* %i, align 4
+  %next = add i32 %i8, 1
+  store i32 %next, i32* %i, align 4
+  %ind9 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind9, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  %result10 = load { i32*, i32 }, { i32*, i32 }* %result, align 8
+  ret { i32*, i32 } %result10
+}
+
+define i32 @main() {
+entry:
+  %shared = alloca { i32*, i32 }, align 8
+  %sq = alloca { i32*, i32 }, align 8
+  %row = alloca { i32*, i32 }, align 8
+  %ind = alloca i32, align 4
+  %grid = alloca { { i32*, i32 }*, i32 }, align 8
+  %primes = alloca { i32*, i32 }, align 8
+  %0 = call i8* @malloc(i64 20)
+  %elements = bitcast i8* %0 to i32*
+  %1 = getelementptr inbounds i32, i32* %elements, i32 0
+  store i32 2, i32* %1, align 4
+  %2 = getelementptr inbounds i32, i32* %elements, i32 1
+  store i32 3, i32* %2, align 4
+  %3 = getelementptr inbounds i32, i32* %elements, i32 2
+  store i32 5, i32* %3, align 4
+  %4 = getelementptr inbounds i32, i32* %elements, i32 3
+  store i32 7, i32* %4, align 4
+  %5 = getelementptr inbounds i32, i32* %elements, i32 4
+  store i32 11, i32* %5, align 4
+  %6 = insertvalue { i32*, i32 } undef, i32* %elements, 0
+  %array = insertvalue { i32*, i32 } %6, i32 5, 1
+  store { i32*, i32 } %array, { i32*, i32 }* %primes, align 8
+  %primes1 = load { i32*, i32 }, { i32*, i32 }* %primes, align 8
+  %7 = call i32 @sum({ i32*, i32 } %primes1)
+  %8 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([8 x i8], [8 x i8]* @strtmp, i32 0, i32 0), i32 %7)
+  %primes2 = load { i32*, i32 }, { i32*, i32 }* %primes, align 8
+  %lentmp = extractvalue { i32*, i32 } %primes2, 1
+  %9 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([8 x i8], [8 x i8]* @strtmp.1, i32 0, i32 0), i32 %lentmp)
+  %primes3 = load { i32*, i32 }, { i32*, i32 }* %primes, align 8
+  %len = extractvalue { i32*, i32 } %primes3, 1
+  %outofrange = icmp uge i32 2, %len
+  br i1 %outofrange, label %panic, label %ok
+
+panic:                                            ; preds = %entry
+  %10 = call i32 @puts(i8* getelementptr inbounds ([45 x i8], [45 x i8]* @1, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %entry
+  %elements4 = extractvalue { i32*, i32 } %primes3, 0
+  %11 = getelementptr inbounds i32, i32* %elements4, i32 2
+  %elem = load i32, i32* %11, align 4
+  %12 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([10 x i8], [10 x i8]* @strtmp.2, i32 0, i32 0), i32 %elem)
+  %13 = call i8* @malloc(i64 32)
+  %elements5 = bitcast i8* %13 to { i32*, i32 }*
+  %14 = getelementptr inbounds { i32*, i32 }, { i32*, i32 }* %elements5, i32 0
+  %15 = call i8* @malloc(i64 8)
+  %elements6 = bitcast i8* %15 to i32*
+  %16 = getelementptr inbounds i32, i32* %elements6, i32 0
+  store i32 1, i32* %16, align 4
+  %17 = getelementptr inbounds i32, i32* %elements6, i32 1
+  store i32 2, i32* %17, align 4
+  %18 = insertvalue { i32*, i32 } undef, i32* %elements6, 0
+  %array7 = insertvalue { i32*, i32 } %18, i32 2, 1
+  store { i32*, i32 } %array7, { i32*, i32 }* %14, align 8
+  %19 = getelementptr inbounds { i32*, i32 }, { i32*, i32 }* %elements5, i32 1
+  %20 = call i8* @malloc(i64 12)
+  %elements8 = bitcast i8* %20 to i32*
+  %21 = getelementptr inbounds i32, i32* %elements8, i32 0
+  store i32 3, i32* %21, align 4
+  %22 = getelementptr inbounds i32, i32* %elements8, i32 1
+  store i32 4, i32* %22, align 4
+  %23 = getelementptr inbounds i32, i32* %elements8, i32 2
+  store i32 5, i32* %23, align 4
+  %24 = insertvalue { i32*, i32 } undef, i32* %elements8, 0
+  %array9 = insertvalue { i32*, i32 } %24, i32 3, 1
+  store { i32*, i32 } %array9, { i32*, i32 }* %19, align 8
+  %25 = insertvalue { { i32*, i32 }*, i32 } undef, { i32*, i32 }* %elements5, 0
+  %array10 = insertvalue { { i32*, i32 }*, i32 } %25, i32 2, 1
+  store { { i32*, i32 }*, i32 } %array10, { { i32*, i32 }*, i32 }* %grid, align 8
+  %grid11 = load { { i32*, i32 }*, i32 }, { { i32*, i32 }*, i32 }* %grid, align 8
+  %len12 = extractvalue { { i32*, i32 }*, i32 } %grid11, 1
+  %outofrange13 = icmp uge i32 1, %len12
+  br i1 %outofrange13, label %panic14, label %ok15
+
+panic14:                                          ; preds = %ok
+  %26 = call i32 @puts(i8* getelementptr inbounds ([44 x i8], [44 x i8]* @2, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok15:                                             ; preds = %ok
+  %elements16 = extractvalue { { i32*, i32 }*, i32 } %grid11, 0
+  %27 = getelementptr inbounds { i32*, i32 }, { i32*, i32 }* %elements16, i32 1
+  %elem17 = load { i32*, i32 }, { i32*, i32 }* %27, align 8
+  %len18 = extractvalue { i32*, i32 } %elem17, 1
+  %outofrange19 = icmp uge i32 2, %len18
+  br i1 %outofrange19, label %panic20, label %ok21
+
+panic20:                                          ; preds = %ok15
+  %28 = call i32 @puts(i8* getelementptr inbounds ([45 x i8], [45 x i8]* @3, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok21:                                             ; preds = %ok15
+  %elements22 = extractvalue { i32*, i32 } %elem17, 0
+  %29 = getelementptr inbounds i32, i32* %elements22, i32 2
+  store i32 50, i32* %29, align 4
+  store i32 0, i32* %ind, align 4
+  %grid23 = load { { i32*, i32 }*, i32 }, { { i32*, i32 }*, i32 }* %grid, align 8
+  %elements24 = extractvalue { { i32*, i32 }*, i32 } %grid23, 0
+  %len25 = extractvalue { { i32*, i32 }*, i32 } %grid23, 1
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %ok21
+  %ind26 = load i32, i32* %ind, align 4
+  %loopcond = icmp slt i32 %ind26, %len25
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %30 = getelementptr inbounds { i32*, i32 }, { i32*, i32 }* %elements24, i32 %ind26
+  %elem27 = load { i32*, i32 }, { i32*, i32 }* %30, align 8
+  store { i32*, i32 } %elem27, { i32*, i32 }* %row, align 8
+  %i = load i32, i32* %ind, align 4
+  %31 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([7 x i8], [7 x i8]* @strtmp.3, i32 0, i32 0), i32 %i)
+  %row28 = load { i32*, i32 }, { i32*, i32 }* %row, align 8
+  %32 = call i32 @sum({ i32*, i32 } %row28)
+  %33 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([9 x i8], [9 x i8]* @strtmp.4, i32 0, i32 0), i32 %32)
+  br label %looplatch
+
+looplatch:                                        ; preds = %loop
+  %ind29 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind29, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  %34 = call { i32*, i32 } @squares()
+  store { i32*, i32 } %34, { i32*, i32 }* %sq, align 8
+  %sq30 = load { i32*, i32 }, { i32*, i32 }* %sq, align 8
+  %sq31 = load { i32*, i32 }, { i32*, i32 }* %sq, align 8
+  %lentmp32 = extractvalue { i32*, i32 } %sq31, 1
+  %subtmp = sub i32 %lentmp32, 1
+  %len33 = extractvalue { i32*, i32 } %sq30, 1
+  %outofrange34 = icmp uge i32 %subtmp, %len33
+  br i1 %outofrange34, label %panic35, label %ok36
+
+panic35:                                          ; preds = %exitloop
+  %35 = call i32 @puts(i8* getelementptr inbounds ([45 x i8], [45 x i8]* @4, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok36:                                             ; preds = %exitloop
+  %elements37 = extractvalue { i32*, i32 } %sq30, 0
+  %36 = getelementptr inbounds i32, i32* %elements37, i32 %subtmp
+  %elem38 = load i32, i32* %36, align 4
+  %37 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([16 x i8], [16 x i8]* @strtmp.5, i32 0, i32 0), i32 %elem38)
+  %sq39 = load { i32*, i32 }, { i32*, i32 }* %sq, align 8
+  store { i32*, i32 } %sq39, { i32*, i32 }* %shared, align 8
+  %shared40 = load { i32*, i32 }, { i32*, i32 }* %shared, align 8
+  %len41 = extractvalue { i32*, i32 } %shared40, 1
+  %outofrange42 = icmp uge i32 0, %len41
+  br i1 %outofrange42, label %panic43, label %ok44
+
+panic43:                                          ; preds = %ok36
+  %38 = call i32 @puts(i8* getelementptr inbounds ([45 x i8], [45 x i8]* @5, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok44:                                             ; preds = %ok36
+  %elements45 = extractvalue { i32*, i32 } %shared40, 0
+  %39 = getelementptr inbounds i32, i32* %elements45, i32 0
+  store i32 100, i32* %39, align 4
+  %sq46 = load { i32*, i32 }, { i32*, i32 }* %sq, align 8
+  %len47 = extractvalue { i32*, i32 } %sq46, 1
+  %outofrange48 = icmp uge i32 0, %len47
+  br i1 %outofrange48, label %panic49, label %ok50
+
+panic49:                                          ; preds = %ok44
+  %40 = call i32 @puts(i8* getelementptr inbounds ([45 x i8], [45 x i8]* @6, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok50:                                             ; preds = %ok44
+  %elements51 = extractvalue { i32*, i32 } %sq46, 0
+  %41 = getelementptr inbounds i32, i32* %elements51, i32 0
+  %elem52 = load i32, i32* %41, align 4
+  %42 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.6, i32 0, i32 0), i32 %elem52)
+  %strlen = call i64 @strlen(i8* getelementptr inbounds ([6 x i8], [6 x i8]* @strtmp.8, i32 0, i32 0))
+  %lentmp53 = trunc i64 %strlen to i32
+  %43 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([10 x i8], [10 x i8]* @strtmp.7, i32 0, i32 0), i32 %lentmp53)
+  %primes54 = load { i32*, i32 }, { i32*, i32 }* %primes, align 8
+  %len55 = extractvalue { i32*, i32 } %primes54, 1
+  %outofrange56 = icmp uge i32 5, %len55
+  br i1 %outofrange56, label %panic57, label %ok58
+
+panic57:                                          ; preds = %ok50
+  %44 = call i32 @puts(i8* getelementptr inbounds ([45 x i8], [45 x i8]* @7, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok58:                                             ; preds = %ok50
+  %elements59 = extractvalue { i32*, i32 } %primes54, 0
+  %45 = getelementptr inbounds i32, i32* %elements59, i32 5
+  %elem60 = load i32, i32* %45, align 4
+  %46 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([17 x i8], [17 x i8]* @strtmp.9, i32 0, i32 0), i32 %elem60)
+  ret i32 0
+}
+
+declare i8* @malloc(i64)
+
+declare i32 @puts(i8*)
+
+declare void @exit(i32)
+
+declare i64 @strlen(i8*)
@@ -0,0 +1,252 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+%struct.Event = type { i8*, i32 }
+
+@atom.ok = private constant [3 x i8] c"ok\00"
+@atom.not_found = private constant [10 x i8] c"not_found\00"
+@atom.error = private constant [6 x i8] c"error\00"
+@strtmp = private unnamed_addr constant [5 x i8] c"fine\00", align 1
+@strtmp.1 = private unnamed_addr constant [8 x i8] c"missing\00", align 1
+@strtmp.2 = private unnamed_addr constant [7 x i8] c"broken\00", align 1
+@strtmp.3 = private unnamed_addr constant [6 x i8] c"start\00", align 1
+@strtmp.4 = private unnamed_addr constant [5 x i8] c"stop\00", align 1
+@strtmp.5 = private unnamed_addr constant [5 x i8] c" %d\0A\00", align 1
+@strtmp.6 = private unnamed_addr constant [13 x i8] c"ok is ok %d\0A\00", align 1
+@strtmp.7 = private unnamed_addr constant [13 x i8] c"mismatch %d\0A\00", align 1
+@strtmp.8 = private unnamed_addr constant [6 x i8] c"start\00", align 1
+@strtmp.9 = private unnamed_addr constant [5 x i8] c"stop\00", align 1
+@strtmp.10 = private unnamed_addr constant [6 x i8] c"pause\00", align 1
+@strtmp.11 = private unnamed_addr constant [20 x i8] c"unknown command %d\0A\00", align 1
+@strtmp.12 = private unnamed_addr constant [12 x i8] c"command %d\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define i8* @status(i32 %code) {
+entry:
+  %code1 = alloca i32, align 4
+  store i32 %code, i32* %code1, align 4
+  %code2 = load i32, i32* %code1, align 4
+  switch i32 %code2, label %matcharm4 [
+    i32 200, label %matcharm
+    i32 404, label %matcharm3
+  ]
+
+matcharm:                                         ; preds = %entry
+  br label %matchexit
+
+matcharm3:                                        ; preds = %entry
+  br label %matchexit
+
+matcharm4:                                        ; preds = %entry
+  br label %matchexit
+
+matchexit:                                        ; preds = %matcharm4, %matcharm3, %matcharm
+  %matchtmp = phi i8* [ getelementptr inbounds ([3 x i8], [3 x i8]* @atom.ok, i32 0, i32 0), %matcharm ], [ getelementptr inbounds ([10 x i8], [10 x i8]* @atom.not_found, i32 0, i32 0), %matcharm3 ], [ getelementptr inbounds ([6 x i8], [6 x i8]* @atom.error, i32 0, i32 0), %matcharm4 ]
+  ret i8* %matchtmp
+}
+
+define i8* @describe(i8* %a) {
+entry:
+  %a1 = alloca i8*, align 8
+  store i8* %a, i8** %a1, align 8
+  %a2 = load i8*, i8** %a1, align 8
+  %cmptmp = icmp eq i8* %a2, getelementptr inbounds ([3 x i8], [3 x i8]* @atom.ok, i32 0, i32 0)
+  br i1 %cmptmp, label %matcharm, label %matchnext
+
+matcharm:                                         ; preds = %entry
+  br label %matchexit
+
+matcharm3:                                        ; preds = %matchnext
+  br label %matchexit
+
+matcharm4:                                        ; preds = %matchnext6
+  br label %matchexit
+
+matchexit:                                        ; preds = %matcharm4, %matcharm3, %matcharm
+  %matchtmp = phi i8* [ getelementptr inbounds ([5 x i8], [5 x i8]* @strtmp, i32 0, i32 0), %matcharm ], [ getelementptr inbounds ([8 x i8], [8 x i8]* @strtmp.1, i32 0, i32 0), %matcharm3 ], [ getelementptr inbounds ([7 x i8], [7 x i8]* @strtmp.2, i32 0, i32 0), %matcharm4 ]
+  ret i8* %matchtmp
+
+matchnext:                                        ; preds = %entry
+  %cmptmp5 = icmp eq i8* %a2, getelementptr inbounds ([10 x i8], [10 x i8]* @atom.not_found, i32 0, i32 0)
+  br i1 %cmptmp5, label %matcharm3, label %matchnext6
+
+matchnext6:                                       ; preds = %matchnext
+  br label %matcharm4
+}
+
+define i32 @command(i8* %name) {
+entry:
+  %name1 = alloca i8*, align 8
+  store i8* %name, i8** %name1, align 8
+  %name2 = load i8*, i8** %name1, align 8
+  %strcmptmp = call i32 @strcmp(i8* %name2, i8* getelementptr inbounds ([6 x i8], [6 x i8]* @strtmp.3, i32 0, i32 0))
+  %cmptmp = icmp eq i32 %strcmptmp, 0
+  br i1 %cmptmp, label %matcharm, label %matchnext
+
+matcharm:                                         ; preds = %entry
+  br label %matchexit
+
+matcharm3:                                        ; preds = %matchnext
+  br label %matchexit
+
+matcharm4:                                        ; preds = %matchnext7
+  br label %matchexit
+
+matchexit:                                        ; preds = %matcharm4, %matcharm3, %matcharm
+  %matchtmp = phi i32 [ 1, %matcharm ], [ 2, %matcharm3 ], [ 0, %matcharm4 ]
+  ret i32 %matchtmp
+
+matchnext:                                        ; preds = %entry
+  %strcmptmp5 = call i32 @strcmp(i8* %name2, i8* getelementptr inbounds ([5 x i8], [5 x i8]* @strtmp.4, i32 0, i32 0))
+  %cmptmp6 = icmp eq i32 %strcmptmp5, 0
+  br i1 %cmptmp6, label %matcharm3, label %matchnext7
+
+matchnext7:                                       ; preds = %matchnext
+  br label %matcharm4
+}
+
+define i32 @main() {
+entry:
+  %name = alloca i8*, align 8
+  %ind23 = alloca i32, align 4
+  %e = alloca %struct.Event, align 8
+  %ind = alloca i32, align 4
+  %events = alloca { %struct.Event*, i32 }, align 8
+  %0 = call i8* @malloc(i64 48)
+  %elements = bitcast i8* %0 to %struct.Event*
+  %1 = getelementptr inbounds %struct.Event, %struct.Event* %elements, i32 0
+  store %struct.Event { i8* getelementptr inbounds ([3 x i8], [3 x i8]* @atom.ok, i32 0, i32 0), i32 200 }, %struct.Event* %1, align 8
+  %2 = getelementptr inbounds %struct.Event, %struct.Event* %elements, i32 1
+  %3 = call i8* @status(i32 404)
+  %4 = insertvalue %struct.Event undef, i8* %3, 0
+  %5 = insertvalue %struct.Event %4, i32 404, 1
+  store %struct.Event %5, %struct.Event* %2, align 8
+  %6 = getelementptr inbounds %struct.Event, %struct.Event* %elements, i32 2
+  %7 = call i8* @status(i32 500)
+  %8 = insertvalue %struct.Event undef, i8* %7, 0
+  %9 = insertvalue %struct.Event %8, i32 500, 1
+  store %struct.Event %9, %struct.Event* %6, align 8
+  %10 = insertvalue { %struct.Event*, i32 } undef, %struct.Event* %elements, 0
+  %array = insertvalue { %struct.Event*, i32 } %10, i32 3, 1
+  store { %struct.Event*, i32 } %array, { %struct.Event*, i32 }* %events, align 8
+  store i32 0, i32* %ind, align 4
+  %events1 = load { %struct.Event*, i32 }, { %struct.Event*, i32 }* %events, align 8
+  %elements2 = extractvalue { %struct.Event*, i32 } %events1, 0
+  %len = extractvalue { %struct.Event*, i32 } %events1, 1
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %ind3 = load i32, i32* %ind, align 4
+  %loopcond = icmp slt i32 %ind3, %len
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %11 = getelementptr inbounds %struct.Event, %struct.Event* %elements2, i32 %ind3
+  %elem = load %struct.Event, %struct.Event* %11, align 8
+  store %struct.Event %elem, %struct.Event* %e, align 8
+  %e4 = load %struct.Event, %struct.Event* %e, align 8
+  %kind = extractvalue %struct.Event %e4, 0
+  %12 = call i8* @describe(i8* %kind)
+  %13 = call i32 (i8*, ...) @printf(i8* %12, i32 0)
+  %e5 = load %struct.Event, %struct.Event* %e, align 8
+  %code = extractvalue %struct.Event %e5, 1
+  %14 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([5 x i8], [5 x i8]* @strtmp.5, i32 0, i32 0), i32 %code)
+  %e6 = load %struct.Event, %struct.Event* %e, align 8
+  %kind7 = extractvalue %struct.Event %e6, 0
+  %cmptmp = icmp eq i8* %kind7, getelementptr inbounds ([3 x i8], [3 x i8]* @atom.ok, i32 0, i32 0)
+  br i1 %cmptmp, label %then, label %else
+
+then:                                             ; preds = %loop
+  %15 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([13 x i8], [13 x i8]* @strtmp.6, i32 0, i32 0), i32 1)
+  br label %exit
+
+else:                                             ; preds = %loop
+  br label %exit
+
+exit:                                             ; preds = %else, %then
+  %e9 = load %struct.Event, %struct.Event* %e, align 8
+  %kind10 = extractvalue %struct.Event %e9, 0
+  %e11 = load %struct.Event, %struct.Event* %e, align 8
+  %code12 = extractvalue %struct.Event %e11, 1
+  %16 = call i8* @status(i32 %code12)
+  %cmptmp13 = icmp ne i8* %kind10, %16
+  br i1 %cmptmp13, label %then14, label %else15
+
+then14:                                           ; preds = %exit
+  %e16 = load %struct.Event, %struct.Event* %e, align 8
+  %code17 = extractvalue %struct.Event %e16, 1
+  %17 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([13 x i8], [13 x i8]* @strtmp.7, i32 0, i32 0), i32 %code17)
+  br label %exit8
+
+else15:                                           ; preds = %exit
+  br label %exit8
+
+exit8:                                            ; preds = %else15, %then14
+  br label %looplatch
+
+looplatch:                                        ; preds = %exit8
+  %ind18 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind18, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  store i32 0, i32* %ind23, align 4
+  %18 = call i8* @malloc(i64 24)
+  %elements24 = bitcast i8* %18 to i8**
+  %19 = getelementptr inbounds i8*, i8** %elements24, i32 0
+  store i8* getelementptr inbounds ([6 x i8], [6 x i8]* @strtmp.8, i32 0, i32 0), i8** %19, align 8
+  %20 = getelementptr inbounds i8*, i8** %elements24, i32 1
+  store i8* getelementptr inbounds ([5 x i8], [5 x i8]* @strtmp.9, i32 0, i32 0), i8** %20, align 8
+  %21 = getelementptr inbounds i8*, i8** %elements24, i32 2
+  store i8* getelementptr inbounds ([6 x i8], [6 x i8]* @strtmp.10, i32 0, i32 0), i8** %21, align 8
+  %22 = insertvalue { i8**, i32 } undef, i8** %elements24, 0
+  %array25 = insertvalue { i8**, i32 } %22, i32 3, 1
+  %elements26 = extractvalue { i8**, i32 } %array25, 0
+  %len27 = extractvalue { i8**, i32 } %array25, 1
+  br label %loopheader19
+
+loopheader19:                                     ; preds = %looplatch21, %exitloop
+  %ind28 = load i32, i32* %ind23, align 4
+  %loopcond29 = icmp slt i32 %ind28, %len27
+  br i1 %loopcond29, label %loop20, label %exitloop22
+
+loop20:                                           ; preds = %loopheader19
+  %23 = getelementptr inbounds i8*, i8** %elements26, i32 %ind28
+  %elem30 = load i8*, i8** %23, align 8
+  store i8* %elem30, i8** %name, align 8
+  %name31 = load i8*, i8** %name, align 8
+  %24 = call i32 @command(i8* %name31)
+  switch i32 %24, label %matcharm32 [
+    i32 0, label %matcharm
+  ]
+
+matcharm:                                         ; preds = %loop20
+  %25 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([20 x i8], [20 x i8]* @strtmp.11, i32 0, i32 0), i32 0)
+  br label %matchexit
+
+matcharm32:                                       ; preds = %loop20
+  %name33 = load i8*, i8** %name, align 8
+  %26 = call i32 @command(i8* %name33)
+  %27 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([12 x i8], [12 x i8]* @strtmp.12, i32 0, i32 0), i32 %26)
+  br label %matchexit
+
+matchexit:                                        ; preds = %matcharm32, %matcharm
+  br label %looplatch21
+
+looplatch21:                                      ; preds = %matchexit
+  %ind34 = load i32, i32* %ind23, align 4
+  %nextind35 = add i32 %ind34, 1
+  store i32 %nextind35, i32* %ind23, align 4
+  br label %loopheader19
+
+exitloop22:                                       ; preds = %loopheader19
+  ret i32 0
+}
+
+declare i32 @strcmp(i8*, i8*)
+
+declare i8* @malloc(i64)
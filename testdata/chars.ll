@@ -0,0 +1,176 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@strtmp = private unnamed_addr constant [5 x i8] c"r2d2\00", align 1
+@strtmp.1 = private unnamed_addr constant [3 x i8] c"\09'\00", align 1
+@strtmp.2 = private unnamed_addr constant [6 x i8] c"tab%c\00", align 1
+@strtmp.3 = private unnamed_addr constant [8 x i8] c"quote%c\00", align 1
+@strtmp.4 = private unnamed_addr constant [10 x i8] c"digit %c\0A\00", align 1
+@strtmp.5 = private unnamed_addr constant [11 x i8] c"letter %c\0A\00", align 1
+@strtmp.6 = private unnamed_addr constant [17 x i8] c"first digit: %c\0A\00", align 1
+@strtmp.7 = private unnamed_addr constant [5 x i8] c"abc7\00", align 1
+@strtmp.8 = private unnamed_addr constant [14 x i8] c"no digit: %c\0A\00", align 1
+@strtmp.9 = private unnamed_addr constant [4 x i8] c"abc\00", align 1
+@strtmp.10 = private unnamed_addr constant [4 x i8] c"%c\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define i1 @isDigit(i8 %c) {
+entry:
+  %c1 = alloca i8, align 1
+  store i8 %c, i8* %c1, align 1
+  %c2 = load i8, i8* %c1, align 1
+  %cmptmp = icmp uge i8 %c2, 48
+  br i1 %cmptmp, label %logicrhs, label %logicexit
+
+logicrhs:                                         ; preds = %entry
+  %c3 = load i8, i8* %c1, align 1
+  %cmptmp4 = icmp ule i8 %c3, 57
+  br label %logicexit
+
+logicexit:                                        ; preds = %logicrhs, %entry
+  %logictmp = phi i1 [ false, %entry ], [ %cmptmp4, %logicrhs ]
+  ret i1 %logictmp
+}
+
+define i8 @firstDigit(i8* %s) {
+entry:
+  %c = alloca i8, align 1
+  %ind = alloca i32, align 4
+  %s1 = alloca i8*, align 8
+  store i8* %s, i8** %s1, align 8
+  store i32 0, i32* %ind, align 4
+  %s2 = load i8*, i8** %s1, align 8
+  %len = call i64 @strlen(i8* %s2)
+  %len3 = trunc i64 %len to i32
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %ind4 = load i32, i32* %ind, align 4
+  %loopcond = icmp slt i32 %ind4, %len3
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %0 = getelementptr inbounds i8, i8* %s2, i32 %ind4
+  %char = load i8, i8* %0, align 1
+  store i8 %char, i8* %c, align 1
+  %c5 = load i8, i8* %c, align 1
+  %1 = call i1 @isDigit(i8 %c5)
+  br i1 %1, label %then, label %else
+
+then:                                             ; preds = %loop
+  %c6 = load i8, i8* %c, align 1
+  ret i8 %c6
+
+else:                                             ; preds = %loop
+  br label %exit
+
+exit:                                             ; preds = %else
+  br label %looplatch
+
+looplatch:                                        ; preds = %exit
+  %ind7 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind7, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  ret i8 63
+}
+
+define i32 @main() {
+entry:
+  %c = alloca i8, align 1
+  %ind = alloca i32, align 4
+  %word = alloca i8*, align 8
+  %concattmp = call i8* @novum.strconcat(i8* getelementptr inbounds ([5 x i8], [5 x i8]* @strtmp, i32 0, i32 0), i8* getelementptr inbounds ([3 x i8], [3 x i8]* @strtmp.1, i32 0, i32 0))
+  store i8* %concattmp, i8** %word, align 8
+  store i32 0, i32* %ind, align 4
+  %word1 = load i8*, i8** %word, align 8
+  %len = call i64 @strlen(i8* %word1)
+  %len2 = trunc i64 %len to i32
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %ind3 = load i32, i32* %ind, align 4
+  %loopcond = icmp slt i32 %ind3, %len2
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %0 = getelementptr inbounds i8, i8* %word1, i32 %ind3
+  %char = load i8, i8* %0, align 1
+  store i8 %char, i8* %c, align 1
+  %c4 = load i8, i8* %c, align 1
+  %cmptmp = icmp eq i8 %c4, 9
+  br i1 %cmptmp, label %then, label %else
+
+then:                                             ; preds = %loop
+  %1 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([6 x i8], [6 x i8]* @strtmp.2, i32 0, i32 0), i32 10)
+  br label %exit
+
+else:                                             ; preds = %loop
+  %c5 = load i8, i8* %c, align 1
+  %cmptmp6 = icmp eq i8 %c5, 39
+  br i1 %cmptmp6, label %then7, label %else8
+
+then7:                                            ; preds = %else
+  %2 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([8 x i8], [8 x i8]* @strtmp.3, i32 0, i32 0), i32 10)
+  br label %exit
+
+else8:                                            ; preds = %else
+  %c9 = load i8, i8* %c, align 1
+  %3 = call i1 @isDigit(i8 %c9)
+  br i1 %3, label %then10, label %else11
+
+then10:                                           ; preds = %else8
+  %c12 = load i8, i8* %c, align 1
+  %promoted = zext i8 %c12 to i32
+  %4 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([10 x i8], [10 x i8]* @strtmp.4, i32 0, i32 0), i32 %promoted)
+  br label %exit
+
+else11:                                           ; preds = %else8
+  %c13 = load i8, i8* %c, align 1
+  %promoted14 = zext i8 %c13 to i32
+  %5 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.5, i32 0, i32 0), i32 %promoted14)
+  br label %exit
+
+exit:                                             ; preds = %else11, %then10, %then7, %then
+  br label %looplatch
+
+looplatch:                                        ; preds = %exit
+  %ind15 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind15, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  %6 = call i8 @firstDigit(i8* getelementptr inbounds ([5 x i8], [5 x i8]* @strtmp.7, i32 0, i32 0))
+  %promoted16 = zext i8 %6 to i32
+  %7 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([17 x i8], [17 x i8]* @strtmp.6, i32 0, i32 0), i32 %promoted16)
+  %8 = call i8 @firstDigit(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.9, i32 0, i32 0))
+  %promoted17 = zext i8 %8 to i32
+  %9 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.8, i32 0, i32 0), i32 %promoted17)
+  %10 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.10, i32 0, i32 0), i32 92)
+  ret i32 0
+}
+
+declare i64 @strlen(i8*)
+
+declare i8* @malloc(i64)
+
+declare i8* @memcpy(i8*, i8*, i64)
+
+define internal i8* @novum.strconcat(i8* %a, i8* %b) {
+entry:
+  %lena = call i64 @strlen(i8* %a)
+  %lenb = call i64 @strlen(i8* %b)
+  %lenbnull = add i64 %lenb, 1
+  %size = add i64 %lena, %lenbnull
+  %buffer = call i8* @malloc(i64 %size)
+  %0 = call i8* @memcpy(i8* %buffer, i8* %a, i64 %lena)
+  %tail = getelementptr inbounds i8, i8* %buffer, i64 %lena
+  %1 = call i8* @memcpy(i8* %tail, i8* %b, i64 %lenbnull)
+  ret i8* %buffer
+}
@@ -0,0 +1,45 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@strtmp = private unnamed_addr constant [4 x i8] c"%d\0A\00", align 1
+@0 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp.1 = private unnamed_addr constant [4 x i8] c"%d\0A\00", align 1
+@1 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp.2 = private unnamed_addr constant [13 x i8] c"unreachable\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define i32 @main() {
+entry:
+  %zero = alloca i32, align 4
+  store i32 0, i32* %zero, align 4
+  br i1 false, label %panic, label %ok
+
+panic:                                            ; preds = %entry
+  %0 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @0, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %entry
+  %1 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp, i32 0, i32 0), i32 1)
+  %zero1 = load i32, i32* %zero, align 4
+  %iszero = icmp eq i32 %zero1, 0
+  br i1 %iszero, label %panic2, label %ok3
+
+panic2:                                           ; preds = %ok
+  %2 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @1, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok3:                                              ; preds = %ok
+  %divtmp = sdiv i32 7, %zero1
+  %3 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.1, i32 0, i32 0), i32 %divtmp)
+  %4 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([13 x i8], [13 x i8]* @strtmp.2, i32 0, i32 0), i32 0)
+  ret i32 0
+}
+
+declare i32 @puts(i8*)
+
+declare void @exit(i32)
@@ -0,0 +1,327 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+%enum.Shape = type { i32, double, double, double }
+%enum.Tree = type { i32, i32, { %enum.Tree*, i32 } }
+%struct.Scene = type { { %enum.Shape*, i32 } }
+
+@strtmp = private unnamed_addr constant [13 x i8] c"circle %.1f\0A\00", align 1
+@strtmp.1 = private unnamed_addr constant [16 x i8] c"wide rect %.1f\0A\00", align 1
+@strtmp.2 = private unnamed_addr constant [11 x i8] c"rect %.1f\0A\00", align 1
+@strtmp.3 = private unnamed_addr constant [14 x i8] c"nothing %.1f\0A\00", align 1
+@0 = private unnamed_addr constant [44 x i8] c"Panic: index out of range at enums.nv:38:44\00", align 1
+@1 = private unnamed_addr constant [44 x i8] c"Panic: index out of range at enums.nv:38:63\00", align 1
+@strtmp.4 = private unnamed_addr constant [5 x i8] c"zero\00", align 1
+@strtmp.5 = private unnamed_addr constant [4 x i8] c"one\00", align 1
+@strtmp.6 = private unnamed_addr constant [10 x i8] c"minus one\00", align 1
+@strtmp.7 = private unnamed_addr constant [5 x i8] c"many\00", align 1
+@strtmp.8 = private unnamed_addr constant [10 x i8] c"sum %.1f\0A\00", align 1
+@strtmp.9 = private unnamed_addr constant [2 x i8] c"\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define double @area(%enum.Shape %s) {
+entry:
+  %h = alloca double, align 8
+  %w = alloca double, align 8
+  %r = alloca double, align 8
+  %s1 = alloca %enum.Shape, align 8
+  store %enum.Shape %s, %enum.Shape* %s1, align 8
+  %s2 = load %enum.Shape, %enum.Shape* %s1, align 8
+  %tag = extractvalue %enum.Shape %s2, 0
+  switch i32 %tag, label %matchdefault [
+    i32 0, label %matcharm
+    i32 1, label %matcharm3
+    i32 2, label %matcharm4
+  ]
+
+matcharm:                                         ; preds = %entry
+  %r5 = extractvalue %enum.Shape %s2, 1
+  store double %r5, double* %r, align 8
+  %r6 = load double, double* %r, align 8
+  %multmp = fmul double 3.000000e+00, %r6
+  %r7 = load double, double* %r, align 8
+  %multmp8 = fmul double %multmp, %r7
+  br label %matchexit
+
+matcharm3:                                        ; preds = %entry
+  %w9 = extractvalue %enum.Shape %s2, 2
+  store double %w9, double* %w, align 8
+  %h10 = extractvalue %enum.Shape %s2, 3
+  store double %h10, double* %h, align 8
+  %w11 = load double, double* %w, align 8
+  %h12 = load double, double* %h, align 8
+  %multmp13 = fmul double %w11, %h12
+  br label %matchexit
+
+matcharm4:                                        ; preds = %entry
+  br label %matchexit
+
+matchexit:                                        ; preds = %matcharm4, %matcharm3, %matcharm
+  %matchtmp = phi double [ %multmp8, %matcharm ], [ %multmp13, %matcharm3 ], [ 0.000000e+00, %matcharm4 ]
+  ret double %matchtmp
+
+matchdefault:                                     ; preds = %entry
+  unreachable
+}
+
+define void @Shape.describe(%enum.Shape %s) {
+entry:
+  %w = alloca double, align 8
+  %s1 = alloca %enum.Shape, align 8
+  store %enum.Shape %s, %enum.Shape* %s1, align 8
+  %s2 = load %enum.Shape, %enum.Shape* %s1, align 8
+  %tag = extractvalue %enum.Shape %s2, 0
+  switch i32 %tag, label %matcharm4 [
+    i32 0, label %matcharm
+    i32 1, label %matcharm3
+  ]
+
+matcharm:                                         ; preds = %entry
+  %s5 = load %enum.Shape, %enum.Shape* %s1, align 8
+  %0 = call double @area(%enum.Shape %s5)
+  %1 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([13 x i8], [13 x i8]* @strtmp, i32 0, i32 0), double %0)
+  br label %matchexit
+
+matcharm3:                                        ; preds = %entry
+  %w6 = extractvalue %enum.Shape %s2, 2
+  store double %w6, double* %w, align 8
+  %w7 = load double, double* %w, align 8
+  %cmptmp = fcmp ogt double %w7, 1.000000e+01
+  br i1 %cmptmp, label %then, label %else
+
+matcharm4:                                        ; preds = %entry
+  %2 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.3, i32 0, i32 0), double 0.000000e+00)
+  br label %matchexit
+
+matchexit:                                        ; preds = %matcharm4, %exit, %matcharm
+  ret void
+
+then:                                             ; preds = %matcharm3
+  %w8 = load double, double* %w, align 8
+  %3 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([16 x i8], [16 x i8]* @strtmp.1, i32 0, i32 0), double %w8)
+  ret void
+
+else:                                             ; preds = %matcharm3
+  br label %exit
+
+exit:                                             ; preds = %else
+  %s9 = load %enum.Shape, %enum.Shape* %s1, align 8
+  %4 = call double @area(%enum.Shape %s9)
+  %5 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.2, i32 0, i32 0), double %4)
+  br label %matchexit
+}
+
+define i32 @sum(%enum.Tree %t) {
+entry:
+  %children = alloca { %enum.Tree*, i32 }, align 8
+  %n = alloca i32, align 4
+  %t1 = alloca %enum.Tree, align 8
+  store %enum.Tree %t, %enum.Tree* %t1, align 8
+  %t2 = load %enum.Tree, %enum.Tree* %t1, align 8
+  %tag = extractvalue %enum.Tree %t2, 0
+  switch i32 %tag, label %matchdefault [
+    i32 0, label %matcharm
+    i32 1, label %matcharm3
+  ]
+
+matcharm:                                         ; preds = %entry
+  %n4 = extractvalue %enum.Tree %t2, 1
+  store i32 %n4, i32* %n, align 4
+  %n5 = load i32, i32* %n, align 4
+  br label %matchexit
+
+matcharm3:                                        ; preds = %entry
+  %children6 = extractvalue %enum.Tree %t2, 2
+  store { %enum.Tree*, i32 } %children6, { %enum.Tree*, i32 }* %children, align 8
+  %children7 = load { %enum.Tree*, i32 }, { %enum.Tree*, i32 }* %children, align 8
+  %len = extractvalue { %enum.Tree*, i32 } %children7, 1
+  %outofrange = icmp uge i32 0, %len
+  br i1 %outofrange, label %panic, label %ok
+
+matchdefault:                                     ; preds = %entry
+  unreachable
+
+panic:                                            ; preds = %matcharm3
+  %0 = call i32 @puts(i8* getelementptr inbounds ([44 x i8], [44 x i8]* @0, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %matcharm3
+  %elements = extractvalue { %enum.Tree*, i32 } %children7, 0
+  %1 = getelementptr inbounds %enum.Tree, %enum.Tree* %elements, i32 0
+  %elem = load %enum.Tree, %enum.Tree* %1, align 8
+  %2 = call i32 @sum(%enum.Tree %elem)
+  %children8 = load { %enum.Tree*, i32 }, { %enum.Tree*, i32 }* %children, align 8
+  %len9 = extractvalue { %enum.Tree*, i32 } %children8, 1
+  %outofrange10 = icmp uge i32 1, %len9
+  br i1 %outofrange10, label %panic11, label %ok12
+
+panic11:                                          ; preds = %ok
+  %3 = call i32 @puts(i8* getelementptr inbounds ([44 x i8], [44 x i8]* @1, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok12:                                             ; preds = %ok
+  %elements13 = extractvalue { %enum.Tree*, i32 } %children8, 0
+  %4 = getelementptr inbounds %enum.Tree, %enum.Tree* %elements13, i32 1
+  %elem14 = load %enum.Tree, %enum.Tree* %4, align 8
+  %5 = call i32 @sum(%enum.Tree %elem14)
+  %addtmp = add i32 %2, %5
+  br label %matchexit
+
+matchexit:                                        ; preds = %ok12, %matcharm
+  %matchtmp = phi i32 [ %n5, %matcharm ], [ %addtmp, %ok12 ]
+  ret i32 %matchtmp
+}
+
+define i8* @name(i32 %n) {
+entry:
+  %n1 = alloca i32, align 4
+  store i32 %n, i32* %n1, align 4
+  %n2 = load i32, i32* %n1, align 4
+  switch i32 %n2, label %matcharm5 [
+    i32 0, label %matcharm
+    i32 1, label %matcharm3
+    i32 -1, label %matcharm4
+  ]
+
+matcharm:                                         ; preds = %entry
+  br label %matchexit
+
+matcharm3:                                        ; preds = %entry
+  br label %matchexit
+
+matcharm4:                                        ; preds = %entry
+  br label %matchexit
+
+matcharm5:                                        ; preds = %entry
+  br label %matchexit
+
+matchexit:                                        ; preds = %matcharm5, %matcharm4, %matcharm3, %matcharm
+  %matchtmp = phi i8* [ getelementptr inbounds ([5 x i8], [5 x i8]* @strtmp.4, i32 0, i32 0), %matcharm ], [ getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.5, i32 0, i32 0), %matcharm3 ], [ getelementptr inbounds ([10 x i8], [10 x i8]* @strtmp.6, i32 0, i32 0), %matcharm4 ], [ getelementptr inbounds ([5 x i8], [5 x i8]* @strtmp.7, i32 0, i32 0), %matcharm5 ]
+  ret i8* %matchtmp
+}
+
+define i32 @main() {
+entry:
+  %n = alloca i32, align 4
+  %ind16 = alloca i32, align 4
+  %tree = alloca %enum.Tree, align 8
+  %s = alloca %enum.Shape, align 8
+  %ind = alloca i32, align 4
+  %scene = alloca %struct.Scene, align 8
+  %0 = call i8* @malloc(i64 128)
+  %elements = bitcast i8* %0 to %enum.Shape*
+  %1 = getelementptr inbounds %enum.Shape, %enum.Shape* %elements, i32 0
+  store %enum.Shape { i32 0, double 1.000000e+00, double undef, double undef }, %enum.Shape* %1, align 8
+  %2 = getelementptr inbounds %enum.Shape, %enum.Shape* %elements, i32 1
+  store %enum.Shape { i32 1, double undef, double 2.000000e+00, double 3.000000e+00 }, %enum.Shape* %2, align 8
+  %3 = getelementptr inbounds %enum.Shape, %enum.Shape* %elements, i32 2
+  store %enum.Shape { i32 2, double undef, double undef, double undef }, %enum.Shape* %3, align 8
+  %4 = getelementptr inbounds %enum.Shape, %enum.Shape* %elements, i32 3
+  store %enum.Shape { i32 1, double undef, double 1.200000e+01, double 1.000000e+00 }, %enum.Shape* %4, align 8
+  %5 = insertvalue { %enum.Shape*, i32 } undef, %enum.Shape* %elements, 0
+  %array = insertvalue { %enum.Shape*, i32 } %5, i32 4, 1
+  %6 = insertvalue %struct.Scene undef, { %enum.Shape*, i32 } %array, 0
+  store %struct.Scene %6, %struct.Scene* %scene, align 8
+  store i32 0, i32* %ind, align 4
+  %scene1 = load %struct.Scene, %struct.Scene* %scene, align 8
+  %shapes = extractvalue %struct.Scene %scene1, 0
+  %elements2 = extractvalue { %enum.Shape*, i32 } %shapes, 0
+  %len = extractvalue { %enum.Shape*, i32 } %shapes, 1
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %ind3 = load i32, i32* %ind, align 4
+  %loopcond = icmp slt i32 %ind3, %len
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %7 = getelementptr inbounds %enum.Shape, %enum.Shape* %elements2, i32 %ind3
+  %elem = load %enum.Shape, %enum.Shape* %7, align 8
+  store %enum.Shape %elem, %enum.Shape* %s, align 8
+  %s4 = load %enum.Shape, %enum.Shape* %s, align 8
+  call void @Shape.describe(%enum.Shape %s4)
+  br label %looplatch
+
+looplatch:                                        ; preds = %loop
+  %ind5 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind5, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  %8 = call i8* @malloc(i64 48)
+  %elements6 = bitcast i8* %8 to %enum.Tree*
+  %9 = getelementptr inbounds %enum.Tree, %enum.Tree* %elements6, i32 0
+  store %enum.Tree { i32 0, i32 1, { %enum.Tree*, i32 } undef }, %enum.Tree* %9, align 8
+  %10 = getelementptr inbounds %enum.Tree, %enum.Tree* %elements6, i32 1
+  %11 = call i8* @malloc(i64 48)
+  %elements7 = bitcast i8* %11 to %enum.Tree*
+  %12 = getelementptr inbounds %enum.Tree, %enum.Tree* %elements7, i32 0
+  store %enum.Tree { i32 0, i32 2, { %enum.Tree*, i32 } undef }, %enum.Tree* %12, align 8
+  %13 = getelementptr inbounds %enum.Tree, %enum.Tree* %elements7, i32 1
+  store %enum.Tree { i32 0, i32 3, { %enum.Tree*, i32 } undef }, %enum.Tree* %13, align 8
+  %14 = insertvalue { %enum.Tree*, i32 } undef, %enum.Tree* %elements7, 0
+  %array8 = insertvalue { %enum.Tree*, i32 } %14, i32 2, 1
+  %15 = insertvalue %enum.Tree { i32 1, i32 undef, { %enum.Tree*, i32 } undef }, { %enum.Tree*, i32 } %array8, 2
+  store %enum.Tree %15, %enum.Tree* %10, align 8
+  %16 = insertvalue { %enum.Tree*, i32 } undef, %enum.Tree* %elements6, 0
+  %array9 = insertvalue { %enum.Tree*, i32 } %16, i32 2, 1
+  %17 = insertvalue %enum.Tree { i32 1, i32 undef, { %enum.Tree*, i32 } undef }, { %enum.Tree*, i32 } %array9, 2
+  store %enum.Tree %17, %enum.Tree* %tree, align 8
+  %tree10 = load %enum.Tree, %enum.Tree* %tree, align 8
+  %18 = call i32 @sum(%enum.Tree %tree10)
+  switch i32 %18, label %matcharm11 [
+    i32 6, label %matcharm
+  ]
+
+matcharm:                                         ; preds = %exitloop
+  br label %matchexit
+
+matcharm11:                                       ; preds = %exitloop
+  br label %matchexit
+
+matchexit:                                        ; preds = %matcharm11, %matcharm
+  %matchtmp = phi double [ 6.000000e+00, %matcharm ], [ -1.000000e+00, %matcharm11 ]
+  %addtmp = fadd double 0.000000e+00, %matchtmp
+  %19 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([10 x i8], [10 x i8]* @strtmp.8, i32 0, i32 0), double %addtmp)
+  store i32 0, i32* %ind16, align 4
+  store i32 -1, i32* %n, align 4
+  br label %loopheader12
+
+loopheader12:                                     ; preds = %looplatch14, %matchexit
+  %n17 = load i32, i32* %n, align 4
+  %beforeend = icmp slt i32 %n17, 3
+  %afterend = icmp sgt i32 %n17, 3
+  %loopcond18 = select i1 true, i1 %beforeend, i1 %afterend
+  br i1 %loopcond18, label %loop13, label %exitloop15
+
+loop13:                                           ; preds = %loopheader12
+  %n19 = load i32, i32* %n, align 4
+  %20 = call i8* @name(i32 %n19)
+  %21 = call i32 (i8*, ...) @printf(i8* %20, double 0.000000e+00)
+  %22 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.9, i32 0, i32 0), double 0.000000e+00)
+  br label %looplatch14
+
+looplatch14:                                      ; preds = %loop13
+  %n20 = load i32, i32* %n, align 4
+  %next = add i32 %n20, 1
+  store i32 %next, i32* %n, align 4
+  %ind21 = load i32, i32* %ind16, align 4
+  %nextind22 = add i32 %ind21, 1
+  store i32 %nextind22, i32* %ind16, align 4
+  br label %loopheader12
+
+exitloop15:                                       ; preds = %loopheader12
+  ret i32 0
+}
+
+declare i32 @puts(i8*)
+
+declare void @exit(i32)
+
+declare i8* @malloc(i64)
@@ -0,0 +1,136 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+%struct.Box = type { i64, i64, i64 }
+%struct.Vec2 = type { double, double }
+%struct.Rgb = type { i8, i8, i8 }
+%struct.Pair = type { i32, float }
+%enum.Shape = type { i32, double }
+
+@strtmp = private unnamed_addr constant [4 x i8] c"%d\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+declare { double, double } @add(double, double, double, double)
+
+declare i64 @label(i64)
+
+declare i24 @next(i24)
+
+declare void @sum(%struct.Box* sret(%struct.Box), %struct.Box* byval(%struct.Box))
+
+declare double @area(i64, double)
+
+declare i32 @total(i64, i64)
+
+declare double @last(double, double, double, double, double, double, double, %struct.Vec2* byval(%struct.Vec2))
+
+define void @calls() {
+entry:
+  %l = alloca double, align 8
+  %byref20 = alloca %struct.Vec2, align 8
+  %t = alloca i32, align 4
+  %coerce16 = alloca [2 x i64], align 8
+  %a = alloca double, align 8
+  %coerce13 = alloca [2 x i64], align 8
+  %b = alloca %struct.Box, align 8
+  %byref = alloca %struct.Box, align 8
+  %sret = alloca %struct.Box, align 8
+  %c = alloca %struct.Rgb, align 8
+  %coerce12 = alloca [1 x i64], align 8
+  %coerce10 = alloca [1 x i64], align 8
+  %p = alloca %struct.Pair, align 8
+  %coerce9 = alloca [1 x i64], align 8
+  %coerce7 = alloca [1 x i64], align 8
+  %v = alloca %struct.Vec2, align 8
+  %coerce6 = alloca [2 x i64], align 8
+  %coerce3 = alloca [2 x i64], align 8
+  %coerce = alloca [2 x i64], align 8
+  %0 = bitcast [2 x i64]* %coerce to %struct.Vec2*
+  store %struct.Vec2 { double 1.000000e+00, double 2.000000e+00 }, %struct.Vec2* %0, align 8
+  %1 = bitcast [2 x i64]* %coerce to { double, double }*
+  %2 = getelementptr inbounds { double, double }, { double, double }* %1, i32 0, i32 0
+  %coerce1 = load double, double* %2, align 8
+  %3 = getelementptr inbounds { double, double }, { double, double }* %1, i32 0, i32 1
+  %coerce2 = load double, double* %3, align 8
+  %4 = bitcast [2 x i64]* %coerce3 to %struct.Vec2*
+  store %struct.Vec2 { double 3.000000e+00, double 4.000000e+00 }, %struct.Vec2* %4, align 8
+  %5 = bitcast [2 x i64]* %coerce3 to { double, double }*
+  %6 = getelementptr inbounds { double, double }, { double, double }* %5, i32 0, i32 0
+  %coerce4 = load double, double* %6, align 8
+  %7 = getelementptr inbounds { double, double }, { double, double }* %5, i32 0, i32 1
+  %coerce5 = load double, double* %7, align 8
+  %8 = call { double, double } @add(double %coerce1, double %coerce2, double %coerce4, double %coerce5)
+  %9 = bitcast [2 x i64]* %coerce6 to { double, double }*
+  store { double, double } %8, { double, double }* %9, align 8
+  %10 = bitcast [2 x i64]* %coerce6 to %struct.Vec2*
+  %11 = load %struct.Vec2, %struct.Vec2* %10, align 8
+  store %struct.Vec2 %11, %struct.Vec2* %v, align 8
+  %12 = bitcast [1 x i64]* %coerce7 to %struct.Pair*
+  store %struct.Pair { i32 1, float 5.000000e-01 }, %struct.Pair* %12, align 4
+  %13 = bitcast [1 x i64]* %coerce7 to i64*
+  %coerce8 = load i64, i64* %13, align 8
+  %14 = call i64 @label(i64 %coerce8)
+  %15 = bitcast [1 x i64]* %coerce9 to i64*
+  store i64 %14, i64* %15, align 8
+  %16 = bitcast [1 x i64]* %coerce9 to %struct.Pair*
+  %17 = load %struct.Pair, %struct.Pair* %16, align 4
+  store %struct.Pair %17, %struct.Pair* %p, align 4
+  %18 = bitcast [1 x i64]* %coerce10 to %struct.Rgb*
+  store %struct.Rgb { i8 97, i8 98, i8 99 }, %struct.Rgb* %18, align 1
+  %19 = bitcast [1 x i64]* %coerce10 to i24*
+  %coerce11 = load i24, i24* %19, align 4
+  %20 = call i24 @next(i24 %coerce11)
+  %21 = bitcast [1 x i64]* %coerce12 to i24*
+  store i24 %20, i24* %21, align 4
+  %22 = bitcast [1 x i64]* %coerce12 to %struct.Rgb*
+  %23 = load %struct.Rgb, %struct.Rgb* %22, align 1
+  store %struct.Rgb %23, %struct.Rgb* %c, align 1
+  store %struct.Box { i64 1, i64 2, i64 3 }, %struct.Box* %byref, align 8
+  call void @sum(%struct.Box* sret(%struct.Box) %sret, %struct.Box* byval(%struct.Box) %byref)
+  %24 = load %struct.Box, %struct.Box* %sret, align 8
+  store %struct.Box %24, %struct.Box* %b, align 8
+  %25 = bitcast [2 x i64]* %coerce13 to %enum.Shape*
+  store %enum.Shape { i32 0, double 1.000000e+00 }, %enum.Shape* %25, align 8
+  %26 = bitcast [2 x i64]* %coerce13 to { i64, double }*
+  %27 = getelementptr inbounds { i64, double }, { i64, double }* %26, i32 0, i32 0
+  %coerce14 = load i64, i64* %27, align 8
+  %28 = getelementptr inbounds { i64, double }, { i64, double }* %26, i32 0, i32 1
+  %coerce15 = load double, double* %28, align 8
+  %29 = call double @area(i64 %coerce14, double %coerce15)
+  store double %29, double* %a, align 8
+  %30 = call i8* @malloc(i64 12)
+  %elements = bitcast i8* %30 to i32*
+  %31 = getelementptr inbounds i32, i32* %elements, i32 0
+  store i32 1, i32* %31, align 4
+  %32 = getelementptr inbounds i32, i32* %elements, i32 1
+  store i32 2, i32* %32, align 4
+  %33 = getelementptr inbounds i32, i32* %elements, i32 2
+  store i32 3, i32* %33, align 4
+  %34 = insertvalue { i32*, i32 } undef, i32* %elements, 0
+  %array = insertvalue { i32*, i32 } %34, i32 3, 1
+  %35 = bitcast [2 x i64]* %coerce16 to { i32*, i32 }*
+  store { i32*, i32 } %array, { i32*, i32 }* %35, align 8
+  %36 = bitcast [2 x i64]* %coerce16 to { i64, i64 }*
+  %37 = getelementptr inbounds { i64, i64 }, { i64, i64 }* %36, i32 0, i32 0
+  %coerce17 = load i64, i64* %37, align 8
+  %38 = getelementptr inbounds { i64, i64 }, { i64, i64 }* %36, i32 0, i32 1
+  %coerce18 = load i64, i64* %38, align 8
+  %39 = call i32 @total(i64 %coerce17, i64 %coerce18)
+  store i32 %39, i32* %t, align 4
+  %v19 = load %struct.Vec2, %struct.Vec2* %v, align 8
+  store %struct.Vec2 %v19, %struct.Vec2* %byref20, align 8
+  %40 = call double @last(double 1.000000e+00, double 2.000000e+00, double 3.000000e+00, double 4.000000e+00, double 5.000000e+00, double 6.000000e+00, double 7.000000e+00, %struct.Vec2* byval(%struct.Vec2) %byref20)
+  store double %40, double* %l, align 8
+  ret void
+}
+
+define i32 @main() {
+entry:
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp, i32 0, i32 0), i32 1)
+  ret i32 0
+}
+
+declare i8* @malloc(i64)
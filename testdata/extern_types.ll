@@ -0,0 +1,32 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@strtmp = private unnamed_addr constant [6 x i8] c"%lld\0A\00", align 1
+@strtmp.1 = private unnamed_addr constant [6 x i8] c"%lld\0A\00", align 1
+
+declare i32 @putchar(i32)
+
+declare i64 @printf(i8*, ...)
+
+declare void @exit(i8)
+
+define i32 @main() {
+entry:
+  %n = alloca i64, align 8
+  %last = alloca i32, align 4
+  %0 = call i32 @putchar(i32 72)
+  %1 = call i32 @putchar(i32 105)
+  store i32 %1, i32* %last, align 4
+  %2 = call i32 @putchar(i32 10)
+  %3 = call i64 (i8*, ...) @printf(i8* getelementptr inbounds ([6 x i8], [6 x i8]* @strtmp, i32 0, i32 0), i64 3000000000)
+  store i64 %3, i64* %n, align 8
+  %last1 = load i32, i32* %last, align 4
+  %casttmp = sext i32 %last1 to i64
+  %n2 = load i64, i64* %n, align 8
+  %addtmp = add i64 %casttmp, %n2
+  %4 = call i64 (i8*, ...) @printf(i8* getelementptr inbounds ([6 x i8], [6 x i8]* @strtmp.1, i32 0, i32 0), i64 %addtmp)
+  call void @exit(i8 3)
+  ret i32 0
+}
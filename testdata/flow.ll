@@ -0,0 +1,197 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@0 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp = private unnamed_addr constant [4 x i8] c"%d\0A\00", align 1
+@strtmp.1 = private unnamed_addr constant [4 x i8] c"%d\0A\00", align 1
+@strtmp.2 = private unnamed_addr constant [4 x i8] c"%d\0A\00", align 1
+@strtmp.3 = private unnamed_addr constant [4 x i8] c"%d\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define i32 @sign(i32 %n) {
+entry:
+  %n1 = alloca i32, align 4
+  store i32 %n, i32* %n1, align 4
+  %n2 = load i32, i32* %n1, align 4
+  %cmptmp = icmp slt i32 %n2, 0
+  br i1 %cmptmp, label %then, label %else
+
+then:                                             ; preds = %entry
+  ret i32 -1
+
+else:                                             ; preds = %entry
+  %n3 = load i32, i32* %n1, align 4
+  %cmptmp4 = icmp eq i32 %n3, 0
+  br i1 %cmptmp4, label %then5, label %else6
+
+then5:                                            ; preds = %else
+  ret i32 0
+
+else6:                                            ; preds = %else
+  ret i32 1
+
+exit:                                             ; No predecessors!
+  unreachable
+}
+
+define i32 @firstEven({ i32*, i32 } %values) {
+entry:
+  %v = alloca i32, align 4
+  %ind = alloca i32, align 4
+  %values1 = alloca { i32*, i32 }, align 8
+  store { i32*, i32 } %values, { i32*, i32 }* %values1, align 8
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  br i1 true, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  store i32 0, i32* %ind, align 4
+  %values6 = load { i32*, i32 }, { i32*, i32 }* %values1, align 8
+  %elements = extractvalue { i32*, i32 } %values6, 0
+  %len = extractvalue { i32*, i32 } %values6, 1
+  br label %loopheader2
+
+loopheader2:                                      ; preds = %looplatch4, %loop
+  %ind7 = load i32, i32* %ind, align 4
+  %loopcond = icmp slt i32 %ind7, %len
+  br i1 %loopcond, label %loop3, label %exitloop5
+
+loop3:                                            ; preds = %loopheader2
+  %0 = getelementptr inbounds i32, i32* %elements, i32 %ind7
+  %elem = load i32, i32* %0, align 4
+  store i32 %elem, i32* %v, align 4
+  %v8 = load i32, i32* %v, align 4
+  br i1 false, label %panic, label %ok
+
+panic:                                            ; preds = %loop3
+  %1 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @0, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %loop3
+  %remtmp = srem i32 %v8, 2
+  %cmptmp = icmp eq i32 %remtmp, 0
+  br i1 %cmptmp, label %then, label %else
+
+then:                                             ; preds = %ok
+  %v9 = load i32, i32* %v, align 4
+  ret i32 %v9
+
+else:                                             ; preds = %ok
+  br label %exit
+
+exit:                                             ; preds = %else
+  br label %looplatch4
+
+looplatch4:                                       ; preds = %exit
+  %ind10 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind10, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader2
+
+exitloop5:                                        ; preds = %loopheader2
+  ret i32 -1
+
+looplatch:                                        ; No predecessors!
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  unreachable
+}
+
+define i32 @describe(i32 %n) {
+entry:
+  %n1 = alloca i32, align 4
+  store i32 %n, i32* %n1, align 4
+  %n2 = load i32, i32* %n1, align 4
+  switch i32 %n2, label %matcharm3 [
+    i32 0, label %matcharm
+  ]
+
+matcharm:                                         ; preds = %entry
+  ret i32 10
+
+matcharm3:                                        ; preds = %entry
+  ret i32 20
+
+matchexit:                                        ; No predecessors!
+  unreachable
+}
+
+define void @countdown(i32 %n) {
+entry:
+  %i = alloca i32, align 4
+  %n1 = alloca i32, align 4
+  store i32 %n, i32* %n1, align 4
+  %n2 = load i32, i32* %n1, align 4
+  store i32 %n2, i32* %i, align 4
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  br i1 true, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %i3 = load i32, i32* %i, align 4
+  %cmptmp = icmp eq i32 %i3, 0
+  br i1 %cmptmp, label %then, label %else
+
+then:                                             ; preds = %loop
+  br label %exitloop
+
+else:                                             ; preds = %loop
+  br label %exit
+
+exit:                                             ; preds = %else
+  %i4 = load i32, i32* %i, align 4
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp, i32 0, i32 0), i32 %i4)
+  %i5 = load i32, i32* %i, align 4
+  %subtmp = sub i32 %i5, 1
+  store i32 %subtmp, i32* %i, align 4
+  br label %looplatch
+
+looplatch:                                        ; preds = %exit
+  br label %loopheader
+
+exitloop:                                         ; preds = %then, %loopheader
+  ret void
+}
+
+define i32 @main() {
+entry:
+  %0 = call i32 @sign(i32 -5)
+  %1 = call i32 @sign(i32 0)
+  %addtmp = add i32 %0, %1
+  %2 = call i32 @sign(i32 7)
+  %addtmp1 = add i32 %addtmp, %2
+  %3 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.1, i32 0, i32 0), i32 %addtmp1)
+  %4 = call i8* @malloc(i64 16)
+  %elements = bitcast i8* %4 to i32*
+  %5 = getelementptr inbounds i32, i32* %elements, i32 0
+  store i32 3, i32* %5, align 4
+  %6 = getelementptr inbounds i32, i32* %elements, i32 1
+  store i32 5, i32* %6, align 4
+  %7 = getelementptr inbounds i32, i32* %elements, i32 2
+  store i32 8, i32* %7, align 4
+  %8 = getelementptr inbounds i32, i32* %elements, i32 3
+  store i32 9, i32* %8, align 4
+  %9 = insertvalue { i32*, i32 } undef, i32* %elements, 0
+  %array = insertvalue { i32*, i32 } %9, i32 4, 1
+  %10 = call i32 @firstEven({ i32*, i32 } %array)
+  %11 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.2, i32 0, i32 0), i32 %10)
+  %12 = call i32 @describe(i32 0)
+  %13 = call i32 @describe(i32 1)
+  %addtmp2 = add i32 %12, %13
+  %14 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.3, i32 0, i32 0), i32 %addtmp2)
+  call void @countdown(i32 2)
+  ret i32 0
+}
+
+declare i32 @puts(i8*)
+
+declare void @exit(i32)
+
+declare i8* @malloc(i64)
@@ -0,0 +1,65 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@strtmp = private unnamed_addr constant [4 x i8] c"%s\0A\00", align 1
+@strtmp.1 = private unnamed_addr constant [13 x i8] c"Hello world!\00", align 1
+@strtmp.2 = private unnamed_addr constant [4 x i8] c"abc\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+declare i32 @putchar(i8)
+
+define void @writeln(i8* %msg) {
+entry:
+  %msg1 = alloca i8*, align 8
+  store i8* %msg, i8** %msg1, align 8
+  %msg2 = load i8*, i8** %msg1, align 8
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp, i32 0, i32 0), i8* %msg2)
+  ret void
+}
+
+define i32 @main() {
+entry:
+  %c = alloca i8, align 1
+  %ind = alloca i32, align 4
+  call void @writeln(i8* getelementptr inbounds ([13 x i8], [13 x i8]* @strtmp.1, i32 0, i32 0))
+  store i32 0, i32* %ind, align 4
+  %len = call i64 @strlen(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.2, i32 0, i32 0))
+  %len1 = trunc i64 %len to i32
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %ind2 = load i32, i32* %ind, align 4
+  %loopcond = icmp slt i32 %ind2, %len1
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %0 = getelementptr inbounds i8, i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.2, i32 0, i32 0), i32 %ind2
+  %char = load i8, i8* %0, align 1
+  store i8 %char, i8* %c, align 1
+  %c3 = load i8, i8* %c, align 1
+  %strtmp = call i8* @malloc(i64 2)
+  store i8 %c3, i8* %strtmp, align 1
+  %strend = getelementptr inbounds i8, i8* %strtmp, i64 1
+  store i8 0, i8* %strend, align 1
+  call void @writeln(i8* %strtmp)
+  %c4 = load i8, i8* %c, align 1
+  %1 = call i32 @putchar(i8 %c4)
+  %2 = call i32 @putchar(i8 10)
+  br label %looplatch
+
+looplatch:                                        ; preds = %loop
+  %ind5 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind5, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  ret i32 0
+}
+
+declare i64 @strlen(i8*)
+
+declare i8* @malloc(i64)
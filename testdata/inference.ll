@@ -0,0 +1,312 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+%struct.Box = type { float }
+
+@0 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp = private unnamed_addr constant [12 x i8] c"%lld %u %f\0A\00", align 1
+@1 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@2 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@3 = private unnamed_addr constant [48 x i8] c"Panic: index out of range at inference.nv:48:15\00", align 1
+@4 = private unnamed_addr constant [48 x i8] c"Panic: index out of range at inference.nv:48:27\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define i64 @square(i64 %x) {
+entry:
+  %x1 = alloca i64, align 8
+  store i64 %x, i64* %x1, align 8
+  %x2 = load i64, i64* %x1, align 8
+  %x3 = load i64, i64* %x1, align 8
+  %multmp = mul i64 %x2, %x3
+  ret i64 %multmp
+}
+
+define float @half(float %x) {
+entry:
+  %x1 = alloca float, align 4
+  store float %x, float* %x1, align 4
+  %x2 = load float, float* %x1, align 4
+  br i1 false, label %panic, label %ok
+
+panic:                                            ; preds = %entry
+  %0 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @0, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %entry
+  %divtmp = fdiv float %x2, 2.000000e+00
+  ret float %divtmp
+}
+
+define i1 @Box.heavier(%struct.Box %b, float %than) {
+entry:
+  %than2 = alloca float, align 4
+  %b1 = alloca %struct.Box, align 8
+  store %struct.Box %b, %struct.Box* %b1, align 4
+  store float %than, float* %than2, align 4
+  %b3 = load %struct.Box, %struct.Box* %b1, align 4
+  %weight = extractvalue %struct.Box %b3, 0
+  %than4 = load float, float* %than2, align 4
+  %cmptmp = fcmp ogt float %weight, %than4
+  ret i1 %cmptmp
+}
+
+define i64 @sum({ i8*, i32 } %values) {
+entry:
+  %v = alloca i8, align 1
+  %ind = alloca i32, align 4
+  %total = alloca i64, align 8
+  %values1 = alloca { i8*, i32 }, align 8
+  store { i8*, i32 } %values, { i8*, i32 }* %values1, align 8
+  store i64 0, i64* %total, align 8
+  store i32 0, i32* %ind, align 4
+  %values2 = load { i8*, i32 }, { i8*, i32 }* %values1, align 8
+  %elements = extractvalue { i8*, i32 } %values2, 0
+  %len = extractvalue { i8*, i32 } %values2, 1
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %ind3 = load i32, i32* %ind, align 4
+  %loopcond = icmp slt i32 %ind3, %len
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %0 = getelementptr inbounds i8, i8* %elements, i32 %ind3
+  %elem = load i8, i8* %0, align 1
+  store i8 %elem, i8* %v, align 1
+  %total4 = load i64, i64* %total, align 8
+  %v5 = load i8, i8* %v, align 1
+  %casttmp = zext i8 %v5 to i64
+  %addtmp = add i64 %total4, %casttmp
+  store i64 %addtmp, i64* %total, align 8
+  br label %looplatch
+
+looplatch:                                        ; preds = %loop
+  %ind6 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind6, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  %total7 = load i64, i64* %total, align 8
+  ret i64 %total7
+}
+
+define i64 @pick(i32 %n, i64 %v) {
+entry:
+  %v2 = alloca i64, align 8
+  %n1 = alloca i32, align 4
+  store i32 %n, i32* %n1, align 4
+  store i64 %v, i64* %v2, align 8
+  %n3 = load i32, i32* %n1, align 4
+  switch i32 %n3, label %matcharm4 [
+    i32 0, label %matcharm
+  ]
+
+matcharm:                                         ; preds = %entry
+  %v5 = load i64, i64* %v2, align 8
+  br label %matchexit
+
+matcharm4:                                        ; preds = %entry
+  br label %matchexit
+
+matchexit:                                        ; preds = %matcharm4, %matcharm
+  %matchtmp = phi i64 [ %v5, %matcharm ], [ 0, %matcharm4 ]
+  ret i64 %matchtmp
+}
+
+define i64 @fallback(i32 %n, i64 %v) {
+entry:
+  %v2 = alloca i64, align 8
+  %n1 = alloca i32, align 4
+  store i32 %n, i32* %n1, align 4
+  store i64 %v, i64* %v2, align 8
+  %n3 = load i32, i32* %n1, align 4
+  switch i32 %n3, label %matcharm5 [
+    i32 0, label %matcharm
+    i32 1, label %matcharm4
+  ]
+
+matcharm:                                         ; preds = %entry
+  br label %matchexit
+
+matcharm4:                                        ; preds = %entry
+  br label %matchexit
+
+matcharm5:                                        ; preds = %entry
+  %v6 = load i64, i64* %v2, align 8
+  br label %matchexit
+
+matchexit:                                        ; preds = %matcharm5, %matcharm4, %matcharm
+  %matchtmp = phi i64 [ 3000000000, %matcharm ], [ 1, %matcharm4 ], [ %v6, %matcharm5 ]
+  ret i64 %matchtmp
+}
+
+define i32 @show(i64 %a, i8 %b, float %c) {
+entry:
+  %c3 = alloca float, align 4
+  %b2 = alloca i8, align 1
+  %a1 = alloca i64, align 8
+  store i64 %a, i64* %a1, align 8
+  store i8 %b, i8* %b2, align 1
+  store float %c, float* %c3, align 4
+  %a4 = load i64, i64* %a1, align 8
+  %b5 = load i8, i8* %b2, align 1
+  %c6 = load float, float* %c3, align 4
+  %promoted = zext i8 %b5 to i32
+  %promoted7 = fpext float %c6 to double
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([12 x i8], [12 x i8]* @strtmp, i32 0, i32 0), i64 %a4, i32 %promoted, double %promoted7)
+  ret i32 %0
+}
+
+define i32 @main() {
+entry:
+  %before = alloca { i64*, i32 }, align 8
+  %after = alloca { i64*, i32 }, align 8
+  %box = alloca %struct.Box, align 8
+  %area = alloca i64, align 8
+  %next = alloca i8, align 1
+  %ratio = alloca float, align 4
+  %small = alloca i8, align 1
+  %big = alloca i64, align 8
+  store i64 3000000000, i64* %big, align 8
+  store i8 -6, i8* %small, align 1
+  store float 1.000000e+00, float* %ratio, align 4
+  %small1 = load i8, i8* %small, align 1
+  %addtmp = add i8 %small1, 5
+  store i8 %addtmp, i8* %next, align 1
+  %big2 = load i64, i64* %big, align 8
+  br i1 false, label %panic, label %ok
+
+panic:                                            ; preds = %entry
+  %0 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @1, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %entry
+  %divtmp = sdiv i64 %big2, 1000
+  %1 = call i64 @square(i64 %divtmp)
+  store i64 %1, i64* %area, align 8
+  %2 = call float @half(float 3.000000e+00)
+  %3 = insertvalue %struct.Box undef, float %2, 0
+  store %struct.Box %3, %struct.Box* %box, align 4
+  %area3 = load i64, i64* %area, align 8
+  %big4 = load i64, i64* %big, align 8
+  %iszero = icmp eq i64 %big4, 0
+  br i1 %iszero, label %panic5, label %ok6
+
+panic5:                                           ; preds = %ok
+  %4 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @2, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok6:                                              ; preds = %ok
+  %divtmp7 = sdiv i64 %area3, %big4
+  %next8 = load i8, i8* %next, align 1
+  %box9 = load %struct.Box, %struct.Box* %box, align 4
+  %weight = extractvalue %struct.Box %box9, 0
+  %5 = call i32 @show(i64 %divtmp7, i8 %next8, float %weight)
+  %6 = call i8* @malloc(i64 3)
+  %7 = getelementptr inbounds i8, i8* %6, i32 0
+  store i8 1, i8* %7, align 1
+  %8 = getelementptr inbounds i8, i8* %6, i32 1
+  store i8 2, i8* %8, align 1
+  %9 = getelementptr inbounds i8, i8* %6, i32 2
+  store i8 -1, i8* %9, align 1
+  %10 = insertvalue { i8*, i32 } undef, i8* %6, 0
+  %array = insertvalue { i8*, i32 } %10, i32 3, 1
+  %11 = call i64 @sum({ i8*, i32 } %array)
+  %ratio10 = load float, float* %ratio, align 4
+  %multmp = fmul float %ratio10, 2.500000e+00
+  %12 = call i32 @show(i64 %11, i8 -1, float %multmp)
+  %box11 = load %struct.Box, %struct.Box* %box, align 4
+  %13 = call i1 @Box.heavier(%struct.Box %box11, float 1.000000e+00)
+  br i1 %13, label %then, label %else
+
+then:                                             ; preds = %ok6
+  %14 = call i64 @square(i64 -3)
+  %small12 = load i8, i8* %small, align 1
+  %andtmp = and i8 %small12, 15
+  %15 = call i32 @show(i64 %14, i8 %andtmp, float -5.000000e-01)
+  br label %exit
+
+else:                                             ; preds = %ok6
+  br label %exit
+
+exit:                                             ; preds = %else, %then
+  %16 = call i8* @malloc(i64 16)
+  %elements = bitcast i8* %16 to i64*
+  %17 = getelementptr inbounds i64, i64* %elements, i32 0
+  %big13 = load i64, i64* %big, align 8
+  store i64 %big13, i64* %17, align 8
+  %18 = getelementptr inbounds i64, i64* %elements, i32 1
+  store i64 1, i64* %18, align 8
+  %19 = insertvalue { i64*, i32 } undef, i64* %elements, 0
+  %array14 = insertvalue { i64*, i32 } %19, i32 2, 1
+  store { i64*, i32 } %array14, { i64*, i32 }* %after, align 8
+  %20 = call i8* @malloc(i64 24)
+  %elements15 = bitcast i8* %20 to i64*
+  %21 = getelementptr inbounds i64, i64* %elements15, i32 0
+  store i64 1, i64* %21, align 8
+  %22 = getelementptr inbounds i64, i64* %elements15, i32 1
+  store i64 2, i64* %22, align 8
+  %23 = getelementptr inbounds i64, i64* %elements15, i32 2
+  %big16 = load i64, i64* %big, align 8
+  store i64 %big16, i64* %23, align 8
+  %24 = insertvalue { i64*, i32 } undef, i64* %elements15, 0
+  %array17 = insertvalue { i64*, i32 } %24, i32 3, 1
+  store { i64*, i32 } %array17, { i64*, i32 }* %before, align 8
+  %after18 = load { i64*, i32 }, { i64*, i32 }* %after, align 8
+  %len = extractvalue { i64*, i32 } %after18, 1
+  %outofrange = icmp uge i32 1, %len
+  br i1 %outofrange, label %panic19, label %ok20
+
+panic19:                                          ; preds = %exit
+  %25 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @3, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok20:                                             ; preds = %exit
+  %elements21 = extractvalue { i64*, i32 } %after18, 0
+  %26 = getelementptr inbounds i64, i64* %elements21, i32 1
+  %elem = load i64, i64* %26, align 8
+  %before22 = load { i64*, i32 }, { i64*, i32 }* %before, align 8
+  %len23 = extractvalue { i64*, i32 } %before22, 1
+  %outofrange24 = icmp uge i32 2, %len23
+  br i1 %outofrange24, label %panic25, label %ok26
+
+panic25:                                          ; preds = %ok20
+  %27 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @4, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok26:                                             ; preds = %ok20
+  %elements27 = extractvalue { i64*, i32 } %before22, 0
+  %28 = getelementptr inbounds i64, i64* %elements27, i32 2
+  %elem28 = load i64, i64* %28, align 8
+  %addtmp29 = add i64 %elem, %elem28
+  %small30 = load i8, i8* %small, align 1
+  %29 = call i32 @show(i64 %addtmp29, i8 %small30, float 2.500000e-01)
+  %big31 = load i64, i64* %big, align 8
+  %30 = call i64 @pick(i32 0, i64 %big31)
+  %big32 = load i64, i64* %big, align 8
+  %31 = call i64 @pick(i32 1, i64 %big32)
+  %addtmp33 = add i64 %30, %31
+  %32 = call i32 @show(i64 %addtmp33, i8 0, float 0.000000e+00)
+  %33 = call i64 @fallback(i32 0, i64 7)
+  %34 = call i64 @fallback(i32 1, i64 7)
+  %addtmp34 = add i64 %33, %34
+  %35 = call i64 @fallback(i32 2, i64 7)
+  %addtmp35 = add i64 %addtmp34, %35
+  %36 = call i32 @show(i64 %addtmp35, i8 0, float 0.000000e+00)
+  ret i32 0
+}
+
+declare i32 @puts(i8*)
+
+declare void @exit(i32)
+
+declare i8* @malloc(i64)
@@ -0,0 +1,128 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@strtmp = private unnamed_addr constant [4 x i8] c"%s \00", align 1
+@strtmp.1 = private unnamed_addr constant [7 x i8] c"=> %s\0A\00", align 1
+@strtmp.2 = private unnamed_addr constant [5 x i8] c"true\00", align 1
+@strtmp.3 = private unnamed_addr constant [7 x i8] c"=> %s\0A\00", align 1
+@strtmp.4 = private unnamed_addr constant [6 x i8] c"false\00", align 1
+@strtmp.5 = private unnamed_addr constant [2 x i8] c"a\00", align 1
+@strtmp.6 = private unnamed_addr constant [2 x i8] c"b\00", align 1
+@strtmp.7 = private unnamed_addr constant [2 x i8] c"a\00", align 1
+@strtmp.8 = private unnamed_addr constant [2 x i8] c"b\00", align 1
+@strtmp.9 = private unnamed_addr constant [2 x i8] c"a\00", align 1
+@strtmp.10 = private unnamed_addr constant [2 x i8] c"b\00", align 1
+@strtmp.11 = private unnamed_addr constant [2 x i8] c"a\00", align 1
+@strtmp.12 = private unnamed_addr constant [2 x i8] c"b\00", align 1
+@strtmp.13 = private unnamed_addr constant [2 x i8] c"a\00", align 1
+@strtmp.14 = private unnamed_addr constant [2 x i8] c"a\00", align 1
+@strtmp.15 = private unnamed_addr constant [2 x i8] c"b\00", align 1
+@strtmp.16 = private unnamed_addr constant [2 x i8] c"c\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define i1 @trace(i8* %name, i1 %value) {
+entry:
+  %value2 = alloca i1, align 1
+  %name1 = alloca i8*, align 8
+  store i8* %name, i8** %name1, align 8
+  store i1 %value, i1* %value2, align 1
+  %name3 = load i8*, i8** %name1, align 8
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp, i32 0, i32 0), i8* %name3)
+  %value4 = load i1, i1* %value2, align 1
+  ret i1 %value4
+}
+
+define void @show(i1 %result) {
+entry:
+  %result1 = alloca i1, align 1
+  store i1 %result, i1* %result1, align 1
+  %result2 = load i1, i1* %result1, align 1
+  br i1 %result2, label %then, label %else
+
+then:                                             ; preds = %entry
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([7 x i8], [7 x i8]* @strtmp.1, i32 0, i32 0), i8* getelementptr inbounds ([5 x i8], [5 x i8]* @strtmp.2, i32 0, i32 0))
+  br label %exit
+
+else:                                             ; preds = %entry
+  %1 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([7 x i8], [7 x i8]* @strtmp.3, i32 0, i32 0), i8* getelementptr inbounds ([6 x i8], [6 x i8]* @strtmp.4, i32 0, i32 0))
+  br label %exit
+
+exit:                                             ; preds = %else, %then
+  ret void
+}
+
+define i32 @main() {
+entry:
+  %0 = call i1 @trace(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.5, i32 0, i32 0), i1 false)
+  br i1 %0, label %logicrhs, label %logicexit
+
+logicrhs:                                         ; preds = %entry
+  %1 = call i1 @trace(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.6, i32 0, i32 0), i1 true)
+  br label %logicexit
+
+logicexit:                                        ; preds = %logicrhs, %entry
+  %logictmp = phi i1 [ false, %entry ], [ %1, %logicrhs ]
+  call void @show(i1 %logictmp)
+  %2 = call i1 @trace(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.7, i32 0, i32 0), i1 true)
+  br i1 %2, label %logicrhs1, label %logicexit2
+
+logicrhs1:                                        ; preds = %logicexit
+  %3 = call i1 @trace(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.8, i32 0, i32 0), i1 false)
+  br label %logicexit2
+
+logicexit2:                                       ; preds = %logicrhs1, %logicexit
+  %logictmp3 = phi i1 [ false, %logicexit ], [ %3, %logicrhs1 ]
+  call void @show(i1 %logictmp3)
+  %4 = call i1 @trace(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.9, i32 0, i32 0), i1 true)
+  br i1 %4, label %logicexit5, label %logicrhs4
+
+logicrhs4:                                        ; preds = %logicexit2
+  %5 = call i1 @trace(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.10, i32 0, i32 0), i1 false)
+  br label %logicexit5
+
+logicexit5:                                       ; preds = %logicrhs4, %logicexit2
+  %logictmp6 = phi i1 [ true, %logicexit2 ], [ %5, %logicrhs4 ]
+  call void @show(i1 %logictmp6)
+  %6 = call i1 @trace(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.11, i32 0, i32 0), i1 false)
+  br i1 %6, label %logicexit8, label %logicrhs7
+
+logicrhs7:                                        ; preds = %logicexit5
+  %7 = call i1 @trace(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.12, i32 0, i32 0), i1 true)
+  br label %logicexit8
+
+logicexit8:                                       ; preds = %logicrhs7, %logicexit5
+  %logictmp9 = phi i1 [ true, %logicexit5 ], [ %7, %logicrhs7 ]
+  call void @show(i1 %logictmp9)
+  %8 = call i1 @trace(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.13, i32 0, i32 0), i1 false)
+  %nottmp = xor i1 %8, true
+  br i1 %nottmp, label %logicrhs10, label %logicexit11
+
+logicrhs10:                                       ; preds = %logicexit8
+  br label %logicexit11
+
+logicexit11:                                      ; preds = %logicrhs10, %logicexit8
+  %logictmp12 = phi i1 [ false, %logicexit8 ], [ true, %logicrhs10 ]
+  call void @show(i1 %logictmp12)
+  %9 = call i1 @trace(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.14, i32 0, i32 0), i1 false)
+  br i1 %9, label %logicexit14, label %logicrhs13
+
+logicrhs13:                                       ; preds = %logicexit11
+  %10 = call i1 @trace(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.15, i32 0, i32 0), i1 true)
+  br i1 %10, label %logicrhs15, label %logicexit16
+
+logicexit14:                                      ; preds = %logicexit16, %logicexit11
+  %logictmp18 = phi i1 [ true, %logicexit11 ], [ %logictmp17, %logicexit16 ]
+  call void @show(i1 %logictmp18)
+  ret i32 0
+
+logicrhs15:                                       ; preds = %logicrhs13
+  %11 = call i1 @trace(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.16, i32 0, i32 0), i1 false)
+  br label %logicexit16
+
+logicexit16:                                      ; preds = %logicrhs15, %logicrhs13
+  %logictmp17 = phi i1 [ false, %logicrhs13 ], [ %11, %logicrhs15 ]
+  br label %logicexit14
+}
@@ -0,0 +1,302 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@0 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@1 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp = private unnamed_addr constant [8 x i8] c"odd %d\0A\00", align 1
+@strtmp.1 = private unnamed_addr constant [9 x i8] c"cell %d\0A\00", align 1
+@strtmp.2 = private unnamed_addr constant [19 x i8] c"stopped at row %d\0A\00", align 1
+@strtmp.3 = private unnamed_addr constant [20 x i8] c"first multiple: %d\0A\00", align 1
+@strtmp.4 = private unnamed_addr constant [10 x i8] c"none: %d\0A\00", align 1
+@strtmp.5 = private unnamed_addr constant [12 x i8] c"vowels: %d\0A\00", align 1
+@strtmp.6 = private unnamed_addr constant [16 x i8] c"education. more\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define i32 @firstMultiple(i32 %n, i32 %limit) {
+entry:
+  %i = alloca i32, align 4
+  %limit2 = alloca i32, align 4
+  %n1 = alloca i32, align 4
+  store i32 %n, i32* %n1, align 4
+  store i32 %limit, i32* %limit2, align 4
+  store i32 1, i32* %i, align 4
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %i3 = load i32, i32* %i, align 4
+  %limit4 = load i32, i32* %limit2, align 4
+  %cmptmp = icmp slt i32 %i3, %limit4
+  br i1 %cmptmp, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %i5 = load i32, i32* %i, align 4
+  %n6 = load i32, i32* %n1, align 4
+  %iszero = icmp eq i32 %n6, 0
+  br i1 %iszero, label %panic, label %ok
+
+panic:                                            ; preds = %loop
+  %0 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @0, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %loop
+  %remtmp = srem i32 %i5, %n6
+  %cmptmp7 = icmp eq i32 %remtmp, 0
+  br i1 %cmptmp7, label %then, label %else
+
+then:                                             ; preds = %ok
+  %i8 = load i32, i32* %i, align 4
+  ret i32 %i8
+
+else:                                             ; preds = %ok
+  br label %exit
+
+exit:                                             ; preds = %else
+  %i9 = load i32, i32* %i, align 4
+  %addtmp = add i32 %i9, 1
+  store i32 %addtmp, i32* %i, align 4
+  br label %looplatch
+
+looplatch:                                        ; preds = %exit
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  ret i32 -1
+}
+
+define i32 @countVowels(i8* %s) {
+entry:
+  %c = alloca i8, align 1
+  %ind = alloca i32, align 4
+  %count = alloca i32, align 4
+  %s1 = alloca i8*, align 8
+  store i8* %s, i8** %s1, align 8
+  store i32 0, i32* %count, align 4
+  store i32 0, i32* %ind, align 4
+  %s2 = load i8*, i8** %s1, align 8
+  %len = call i64 @strlen(i8* %s2)
+  %len3 = trunc i64 %len to i32
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %ind4 = load i32, i32* %ind, align 4
+  %loopcond = icmp slt i32 %ind4, %len3
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %0 = getelementptr inbounds i8, i8* %s2, i32 %ind4
+  %char = load i8, i8* %0, align 1
+  store i8 %char, i8* %c, align 1
+  %c5 = load i8, i8* %c, align 1
+  %cmptmp = icmp eq i8 %c5, 97
+  br i1 %cmptmp, label %logicexit, label %logicrhs
+
+logicrhs:                                         ; preds = %loop
+  %c6 = load i8, i8* %c, align 1
+  %cmptmp7 = icmp eq i8 %c6, 101
+  br label %logicexit
+
+logicexit:                                        ; preds = %logicrhs, %loop
+  %logictmp = phi i1 [ true, %loop ], [ %cmptmp7, %logicrhs ]
+  br i1 %logictmp, label %logicexit9, label %logicrhs8
+
+logicrhs8:                                        ; preds = %logicexit
+  %c10 = load i8, i8* %c, align 1
+  %cmptmp11 = icmp eq i8 %c10, 105
+  br label %logicexit9
+
+logicexit9:                                       ; preds = %logicrhs8, %logicexit
+  %logictmp12 = phi i1 [ true, %logicexit ], [ %cmptmp11, %logicrhs8 ]
+  br i1 %logictmp12, label %logicexit14, label %logicrhs13
+
+logicrhs13:                                       ; preds = %logicexit9
+  %c15 = load i8, i8* %c, align 1
+  %cmptmp16 = icmp eq i8 %c15, 111
+  br label %logicexit14
+
+logicexit14:                                      ; preds = %logicrhs13, %logicexit9
+  %logictmp17 = phi i1 [ true, %logicexit9 ], [ %cmptmp16, %logicrhs13 ]
+  br i1 %logictmp17, label %logicexit19, label %logicrhs18
+
+logicrhs18:                                       ; preds = %logicexit14
+  %c20 = load i8, i8* %c, align 1
+  %cmptmp21 = icmp eq i8 %c20, 117
+  br label %logicexit19
+
+logicexit19:                                      ; preds = %logicrhs18, %logicexit14
+  %logictmp22 = phi i1 [ true, %logicexit14 ], [ %cmptmp21, %logicrhs18 ]
+  br i1 %logictmp22, label %then, label %else
+
+then:                                             ; preds = %logicexit19
+  %count23 = load i32, i32* %count, align 4
+  %addtmp = add i32 %count23, 1
+  store i32 %addtmp, i32* %count, align 4
+  br label %looplatch
+
+else:                                             ; preds = %logicexit19
+  br label %exit
+
+exit:                                             ; preds = %else
+  %c25 = load i8, i8* %c, align 1
+  %cmptmp26 = icmp eq i8 %c25, 46
+  br i1 %cmptmp26, label %then27, label %else28
+
+then27:                                           ; preds = %exit
+  br label %exitloop
+
+else28:                                           ; preds = %exit
+  br label %exit24
+
+exit24:                                           ; preds = %else28
+  br label %looplatch
+
+looplatch:                                        ; preds = %exit24, %then
+  %ind29 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind29, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %then27, %loopheader
+  %count30 = load i32, i32* %count, align 4
+  ret i32 %count30
+}
+
+define i32 @main() {
+entry:
+  %col = alloca i32, align 4
+  %row = alloca i32, align 4
+  %i = alloca i32, align 4
+  store i32 0, i32* %i, align 4
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  br i1 true, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %i1 = load i32, i32* %i, align 4
+  %addtmp = add i32 %i1, 1
+  store i32 %addtmp, i32* %i, align 4
+  %i2 = load i32, i32* %i, align 4
+  br i1 false, label %panic, label %ok
+
+panic:                                            ; preds = %loop
+  %0 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @1, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %loop
+  %remtmp = srem i32 %i2, 2
+  %cmptmp = icmp eq i32 %remtmp, 0
+  br i1 %cmptmp, label %then, label %else
+
+then:                                             ; preds = %ok
+  br label %looplatch
+
+else:                                             ; preds = %ok
+  br label %exit
+
+exit:                                             ; preds = %else
+  %i4 = load i32, i32* %i, align 4
+  %cmptmp5 = icmp sgt i32 %i4, 7
+  br i1 %cmptmp5, label %then6, label %else7
+
+then6:                                            ; preds = %exit
+  br label %exitloop
+
+else7:                                            ; preds = %exit
+  br label %exit3
+
+exit3:                                            ; preds = %else7
+  %i8 = load i32, i32* %i, align 4
+  %1 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([8 x i8], [8 x i8]* @strtmp, i32 0, i32 0), i32 %i8)
+  br label %looplatch
+
+looplatch:                                        ; preds = %exit3, %then
+  br label %loopheader
+
+exitloop:                                         ; preds = %then6, %loopheader
+  store i32 0, i32* %row, align 4
+  br label %loopheader9
+
+loopheader9:                                      ; preds = %looplatch11, %exitloop
+  %row13 = load i32, i32* %row, align 4
+  %cmptmp14 = icmp slt i32 %row13, 4
+  br i1 %cmptmp14, label %loop10, label %exitloop12
+
+loop10:                                           ; preds = %loopheader9
+  %row15 = load i32, i32* %row, align 4
+  %addtmp16 = add i32 %row15, 1
+  store i32 %addtmp16, i32* %row, align 4
+  store i32 0, i32* %col, align 4
+  br label %loopheader17
+
+loopheader17:                                     ; preds = %looplatch19, %loop10
+  %col21 = load i32, i32* %col, align 4
+  %cmptmp22 = icmp slt i32 %col21, 4
+  br i1 %cmptmp22, label %loop18, label %exitloop20
+
+loop18:                                           ; preds = %loopheader17
+  %col23 = load i32, i32* %col, align 4
+  %addtmp24 = add i32 %col23, 1
+  store i32 %addtmp24, i32* %col, align 4
+  %col26 = load i32, i32* %col, align 4
+  %row27 = load i32, i32* %row, align 4
+  %cmptmp28 = icmp sgt i32 %col26, %row27
+  br i1 %cmptmp28, label %then29, label %else30
+
+then29:                                           ; preds = %loop18
+  br label %looplatch11
+
+else30:                                           ; preds = %loop18
+  br label %exit25
+
+exit25:                                           ; preds = %else30
+  %row32 = load i32, i32* %row, align 4
+  %col33 = load i32, i32* %col, align 4
+  %multmp = mul i32 %row32, %col33
+  %cmptmp34 = icmp eq i32 %multmp, 6
+  br i1 %cmptmp34, label %then35, label %else36
+
+then35:                                           ; preds = %exit25
+  br label %exitloop12
+
+else36:                                           ; preds = %exit25
+  br label %exit31
+
+exit31:                                           ; preds = %else36
+  %row37 = load i32, i32* %row, align 4
+  %multmp38 = mul i32 %row37, 10
+  %col39 = load i32, i32* %col, align 4
+  %addtmp40 = add i32 %multmp38, %col39
+  %2 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([9 x i8], [9 x i8]* @strtmp.1, i32 0, i32 0), i32 %addtmp40)
+  br label %looplatch19
+
+looplatch19:                                      ; preds = %exit31
+  br label %loopheader17
+
+exitloop20:                                       ; preds = %loopheader17
+  br label %looplatch11
+
+looplatch11:                                      ; preds = %exitloop20, %then29
+  br label %loopheader9
+
+exitloop12:                                       ; preds = %then35, %loopheader9
+  %row41 = load i32, i32* %row, align 4
+  %3 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([19 x i8], [19 x i8]* @strtmp.2, i32 0, i32 0), i32 %row41)
+  %4 = call i32 @firstMultiple(i32 7, i32 100)
+  %5 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([20 x i8], [20 x i8]* @strtmp.3, i32 0, i32 0), i32 %4)
+  %6 = call i32 @firstMultiple(i32 7, i32 5)
+  %7 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([10 x i8], [10 x i8]* @strtmp.4, i32 0, i32 0), i32 %6)
+  %8 = call i32 @countVowels(i8* getelementptr inbounds ([16 x i8], [16 x i8]* @strtmp.6, i32 0, i32 0))
+  %9 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([12 x i8], [12 x i8]* @strtmp.5, i32 0, i32 0), i32 %8)
+  ret i32 0
+}
+
+declare i32 @puts(i8*)
+
+declare void @exit(i32)
+
+declare i64 @strlen(i8*)
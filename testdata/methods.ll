@@ -0,0 +1,151 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+%struct.Point = type { double, double }
+%struct.Size = type { double, double }
+
+@strtmp = private unnamed_addr constant [12 x i8] c"point %.1f\0A\00", align 1
+@strtmp.1 = private unnamed_addr constant [11 x i8] c"size %.1f\0A\00", align 1
+@strtmp.2 = private unnamed_addr constant [13 x i8] c"square %.1f\0A\00", align 1
+@strtmp.3 = private unnamed_addr constant [13 x i8] c"scaled %.1f\0A\00", align 1
+@strtmp.4 = private unnamed_addr constant [10 x i8] c"sum %.1f\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define %struct.Point @Point.scaled(%struct.Point %p, double %by) {
+entry:
+  %by2 = alloca double, align 8
+  %p1 = alloca %struct.Point, align 8
+  store %struct.Point %p, %struct.Point* %p1, align 8
+  store double %by, double* %by2, align 8
+  %p3 = load %struct.Point, %struct.Point* %p1, align 8
+  %x = extractvalue %struct.Point %p3, 0
+  %by4 = load double, double* %by2, align 8
+  %multmp = fmul double %x, %by4
+  %0 = insertvalue %struct.Point undef, double %multmp, 0
+  %p5 = load %struct.Point, %struct.Point* %p1, align 8
+  %y = extractvalue %struct.Point %p5, 1
+  %by6 = load double, double* %by2, align 8
+  %multmp7 = fmul double %y, %by6
+  %1 = insertvalue %struct.Point %0, double %multmp7, 1
+  ret %struct.Point %1
+}
+
+define double @Point.area(%struct.Point %p) {
+entry:
+  %p1 = alloca %struct.Point, align 8
+  store %struct.Point %p, %struct.Point* %p1, align 8
+  %p2 = load %struct.Point, %struct.Point* %p1, align 8
+  %x = extractvalue %struct.Point %p2, 0
+  %p3 = load %struct.Point, %struct.Point* %p1, align 8
+  %y = extractvalue %struct.Point %p3, 1
+  %multmp = fmul double %x, %y
+  ret double %multmp
+}
+
+define double @Size.area(%struct.Size %s) {
+entry:
+  %s1 = alloca %struct.Size, align 8
+  store %struct.Size %s, %struct.Size* %s1, align 8
+  %s2 = load %struct.Size, %struct.Size* %s1, align 8
+  %width = extractvalue %struct.Size %s2, 0
+  %s3 = load %struct.Size, %struct.Size* %s1, align 8
+  %height = extractvalue %struct.Size %s3, 1
+  %multmp = fmul double %width, %height
+  ret double %multmp
+}
+
+define double @"[]float.sum"({ double*, i32 } %xs) {
+entry:
+  %x = alloca double, align 8
+  %ind = alloca i32, align 4
+  %total = alloca double, align 8
+  %xs1 = alloca { double*, i32 }, align 8
+  store { double*, i32 } %xs, { double*, i32 }* %xs1, align 8
+  store double 0.000000e+00, double* %total, align 8
+  store i32 0, i32* %ind, align 4
+  %xs2 = load { double*, i32 }, { double*, i32 }* %xs1, align 8
+  %elements = extractvalue { double*, i32 } %xs2, 0
+  %len = extractvalue { double*, i32 } %xs2, 1
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %ind3 = load i32, i32* %ind, align 4
+  %loopcond = icmp slt i32 %ind3, %len
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %0 = getelementptr inbounds double, double* %elements, i32 %ind3
+  %elem = load double, double* %0, align 8
+  store double %elem, double* %x, align 8
+  %total4 = load double, double* %total, align 8
+  %x5 = load double, double* %x, align 8
+  %addtmp = fadd double %total4, %x5
+  store double %addtmp, double* %total, align 8
+  br label %looplatch
+
+looplatch:                                        ; preds = %loop
+  %ind6 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind6, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  %total7 = load double, double* %total, align 8
+  ret double %total7
+}
+
+define double @area(double %side) {
+entry:
+  %side1 = alloca double, align 8
+  store double %side, double* %side1, align 8
+  %side2 = load double, double* %side1, align 8
+  %side3 = load double, double* %side1, align 8
+  %multmp = fmul double %side2, %side3
+  ret double %multmp
+}
+
+define i32 @main() {
+entry:
+  %s = alloca %struct.Size, align 8
+  %p = alloca %struct.Point, align 8
+  store %struct.Point { double 1.500000e+00, double 2.000000e+00 }, %struct.Point* %p, align 8
+  store %struct.Size { double 3.000000e+00, double 4.000000e+00 }, %struct.Size* %s, align 8
+  %p1 = load %struct.Point, %struct.Point* %p, align 8
+  %0 = call double @Point.area(%struct.Point %p1)
+  %1 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([12 x i8], [12 x i8]* @strtmp, i32 0, i32 0), double %0)
+  %s2 = load %struct.Size, %struct.Size* %s, align 8
+  %2 = call double @Size.area(%struct.Size %s2)
+  %3 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.1, i32 0, i32 0), double %2)
+  %4 = call double @area(double 2.000000e+00)
+  %5 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([13 x i8], [13 x i8]* @strtmp.2, i32 0, i32 0), double %4)
+  %p3 = load %struct.Point, %struct.Point* %p, align 8
+  %6 = call %struct.Point @Point.scaled(%struct.Point %p3, double 2.000000e+00)
+  %7 = call %struct.Point @Point.scaled(%struct.Point %6, double 5.000000e-01)
+  %8 = call %struct.Point @Point.scaled(%struct.Point %7, double 3.000000e+00)
+  %9 = call double @Point.area(%struct.Point %8)
+  %10 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([13 x i8], [13 x i8]* @strtmp.3, i32 0, i32 0), double %9)
+  %11 = call i8* @malloc(i64 24)
+  %elements = bitcast i8* %11 to double*
+  %12 = getelementptr inbounds double, double* %elements, i32 0
+  %p4 = load %struct.Point, %struct.Point* %p, align 8
+  %x = extractvalue %struct.Point %p4, 0
+  store double %x, double* %12, align 8
+  %13 = getelementptr inbounds double, double* %elements, i32 1
+  %p5 = load %struct.Point, %struct.Point* %p, align 8
+  %y = extractvalue %struct.Point %p5, 1
+  store double %y, double* %13, align 8
+  %14 = getelementptr inbounds double, double* %elements, i32 2
+  %s6 = load %struct.Size, %struct.Size* %s, align 8
+  %15 = call double @Size.area(%struct.Size %s6)
+  store double %15, double* %14, align 8
+  %16 = insertvalue { double*, i32 } undef, double* %elements, 0
+  %array = insertvalue { double*, i32 } %16, i32 3, 1
+  %17 = call double @"[]float.sum"({ double*, i32 } %array)
+  %18 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([10 x i8], [10 x i8]* @strtmp.4, i32 0, i32 0), double %17)
+  ret i32 0
+}
+
+declare i8* @malloc(i64)
@@ -0,0 +1,150 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@0 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp = private unnamed_addr constant [15 x i8] c"%lld %u %f %f\0A\00", align 1
+@strtmp.1 = private unnamed_addr constant [15 x i8] c"%lld %u %f %f\0A\00", align 1
+@1 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp.2 = private unnamed_addr constant [15 x i8] c"%lld %u %f %f\0A\00", align 1
+@strtmp.3 = private unnamed_addr constant [15 x i8] c"%lld %u %f %f\0A\00", align 1
+@2 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@3 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp.4 = private unnamed_addr constant [15 x i8] c"%lld %u %f %f\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define double @average({ i16*, i32 } %values) {
+entry:
+  %v = alloca i16, align 2
+  %ind = alloca i32, align 4
+  %total = alloca i64, align 8
+  %values1 = alloca { i16*, i32 }, align 8
+  store { i16*, i32 } %values, { i16*, i32 }* %values1, align 8
+  store i64 0, i64* %total, align 8
+  store i32 0, i32* %ind, align 4
+  %values2 = load { i16*, i32 }, { i16*, i32 }* %values1, align 8
+  %elements = extractvalue { i16*, i32 } %values2, 0
+  %len = extractvalue { i16*, i32 } %values2, 1
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %ind3 = load i32, i32* %ind, align 4
+  %loopcond = icmp slt i32 %ind3, %len
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %0 = getelementptr inbounds i16, i16* %elements, i32 %ind3
+  %elem = load i16, i16* %0, align 2
+  store i16 %elem, i16* %v, align 2
+  %total4 = load i64, i64* %total, align 8
+  %v5 = load i16, i16* %v, align 2
+  %casttmp = sext i16 %v5 to i64
+  %addtmp = add i64 %total4, %casttmp
+  store i64 %addtmp, i64* %total, align 8
+  br label %looplatch
+
+looplatch:                                        ; preds = %loop
+  %ind6 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind6, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  %total7 = load i64, i64* %total, align 8
+  %casttmp8 = sitofp i64 %total7 to double
+  %values9 = load { i16*, i32 }, { i16*, i32 }* %values1, align 8
+  %lentmp = extractvalue { i16*, i32 } %values9, 1
+  %casttmp10 = sitofp i32 %lentmp to double
+  %iszero = fcmp oeq double %casttmp10, 0.000000e+00
+  br i1 %iszero, label %panic, label %ok
+
+panic:                                            ; preds = %exitloop
+  %1 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @0, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %exitloop
+  %divtmp = fdiv double %casttmp8, %casttmp10
+  ret double %divtmp
+}
+
+define i32 @main() {
+entry:
+  %wide = alloca i64, align 8
+  %big = alloca i64, align 8
+  %negative = alloca i8, align 1
+  %wrapped = alloca i64, align 8
+  %small = alloca i8, align 1
+  store i8 -56, i8* %small, align 1
+  %small1 = load i8, i8* %small, align 1
+  %addtmp = add i8 %small1, 100
+  %casttmp = zext i8 %addtmp to i64
+  store i64 %casttmp, i64* %wrapped, align 8
+  store i8 -3, i8* %negative, align 1
+  store i64 1048576, i64* %big, align 8
+  %negative2 = load i8, i8* %negative, align 1
+  %casttmp3 = sext i8 %negative2 to i64
+  store i64 %casttmp3, i64* %wide, align 8
+  %wide4 = load i64, i64* %wide, align 8
+  %big5 = load i64, i64* %big, align 8
+  %multmp = mul i64 %wide4, %big5
+  store i64 %multmp, i64* %wide, align 8
+  %wrapped6 = load i64, i64* %wrapped, align 8
+  %small7 = load i8, i8* %small, align 1
+  %promoted = zext i8 %small7 to i32
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp, i32 0, i32 0), i64 %wrapped6, i32 %promoted, double 1.500000e+00, double 2.500000e+00)
+  %wide8 = load i64, i64* %wide, align 8
+  %negative9 = load i8, i8* %negative, align 1
+  %shrtmp = lshr i8 %negative9, 1
+  br i1 false, label %panic, label %ok
+
+panic:                                            ; preds = %entry
+  %1 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @1, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %entry
+  %promoted10 = zext i8 %shrtmp to i32
+  %2 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.1, i32 0, i32 0), i64 %wide8, i32 %promoted10, double 0x3FD5555560000000, double -7.000000e+00)
+  %3 = call i8* @malloc(i64 6)
+  %elements = bitcast i8* %3 to i16*
+  %4 = getelementptr inbounds i16, i16* %elements, i32 0
+  store i16 1, i16* %4, align 2
+  %5 = getelementptr inbounds i16, i16* %elements, i32 1
+  store i16 2, i16* %5, align 2
+  %6 = getelementptr inbounds i16, i16* %elements, i32 2
+  store i16 4, i16* %6, align 2
+  %7 = insertvalue { i16*, i32 } undef, i16* %elements, 0
+  %array = insertvalue { i16*, i32 } %7, i32 3, 1
+  %8 = call double @average({ i16*, i32 } %array)
+  %casttmp11 = fptrunc double %8 to float
+  %promoted12 = fpext float %casttmp11 to double
+  %9 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.2, i32 0, i32 0), i64 4294967295, i32 97, double %promoted12, double 0.000000e+00)
+  br i1 false, label %panic13, label %ok14
+
+panic13:                                          ; preds = %ok
+  %10 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @2, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok14:                                             ; preds = %ok
+  br i1 false, label %panic15, label %ok16
+
+panic15:                                          ; preds = %ok14
+  %11 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @3, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok16:                                             ; preds = %ok14
+  %12 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.3, i32 0, i32 0), i64 -3, i32 62, double 0x3FB99999A0000000, double 0x4170000000000000)
+  %13 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.4, i32 0, i32 0), i64 44, i32 65, double 0.000000e+00, double 6.553500e+04)
+  ret i32 0
+}
+
+declare i32 @puts(i8*)
+
+declare void @exit(i32)
+
+declare i8* @malloc(i64)
@@ -0,0 +1,380 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@strtmp = private unnamed_addr constant [9 x i8] c"x = %d, \00", align 1
+@strtmp.1 = private unnamed_addr constant [8 x i8] c"y = %d\0A\00", align 1
+@strtmp.2 = private unnamed_addr constant [14 x i8] c"  x + y = %d\0A\00", align 1
+@strtmp.3 = private unnamed_addr constant [14 x i8] c"  x - y = %d\0A\00", align 1
+@strtmp.4 = private unnamed_addr constant [14 x i8] c"  x * y = %d\0A\00", align 1
+@strtmp.5 = private unnamed_addr constant [14 x i8] c"  x / y = %d\0A\00", align 1
+@0 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp.6 = private unnamed_addr constant [15 x i8] c"  x %% y = %d\0A\00", align 1
+@1 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp.7 = private unnamed_addr constant [14 x i8] c"  x & y = %d\0A\00", align 1
+@strtmp.8 = private unnamed_addr constant [14 x i8] c"  x | y = %d\0A\00", align 1
+@strtmp.9 = private unnamed_addr constant [14 x i8] c"  x ^ y = %d\0A\00", align 1
+@strtmp.10 = private unnamed_addr constant [15 x i8] c"  x << y = %d\0A\00", align 1
+@strtmp.11 = private unnamed_addr constant [15 x i8] c"  x >> y = %d\0A\00", align 1
+@strtmp.12 = private unnamed_addr constant [11 x i8] c"  -x = %d\0A\00", align 1
+@strtmp.13 = private unnamed_addr constant [14 x i8] c"  x < y = %d\0A\00", align 1
+@strtmp.14 = private unnamed_addr constant [15 x i8] c"  x <= y = %d\0A\00", align 1
+@strtmp.15 = private unnamed_addr constant [14 x i8] c"  x > y = %d\0A\00", align 1
+@strtmp.16 = private unnamed_addr constant [15 x i8] c"  x >= y = %d\0A\00", align 1
+@strtmp.17 = private unnamed_addr constant [15 x i8] c"  x == y = %d\0A\00", align 1
+@strtmp.18 = private unnamed_addr constant [15 x i8] c"  x != y = %d\0A\00", align 1
+@strtmp.19 = private unnamed_addr constant [7 x i8] c"float\0A\00", align 1
+@strtmp.20 = private unnamed_addr constant [20 x i8] c"  x + y == 8.5: %d\0A\00", align 1
+@strtmp.21 = private unnamed_addr constant [20 x i8] c"  x - y == 4.5: %d\0A\00", align 1
+@strtmp.22 = private unnamed_addr constant [21 x i8] c"  x * y == 13.0: %d\0A\00", align 1
+@strtmp.23 = private unnamed_addr constant [21 x i8] c"  x / y == 3.25: %d\0A\00", align 1
+@2 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp.24 = private unnamed_addr constant [21 x i8] c"  x %% y == 0.5: %d\0A\00", align 1
+@3 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp.25 = private unnamed_addr constant [21 x i8] c"  -x == 0.0 - x: %d\0A\00", align 1
+@strtmp.26 = private unnamed_addr constant [14 x i8] c"  x < y = %d\0A\00", align 1
+@strtmp.27 = private unnamed_addr constant [15 x i8] c"  x <= y = %d\0A\00", align 1
+@strtmp.28 = private unnamed_addr constant [14 x i8] c"  x > y = %d\0A\00", align 1
+@strtmp.29 = private unnamed_addr constant [15 x i8] c"  x >= y = %d\0A\00", align 1
+@strtmp.30 = private unnamed_addr constant [15 x i8] c"  x == y = %d\0A\00", align 1
+@strtmp.31 = private unnamed_addr constant [15 x i8] c"  x != y = %d\0A\00", align 1
+@strtmp.32 = private unnamed_addr constant [15 x i8] c"  x <= x = %d\0A\00", align 1
+@strtmp.33 = private unnamed_addr constant [15 x i8] c"  x >= x = %d\0A\00", align 1
+@strtmp.34 = private unnamed_addr constant [9 x i8] c"x = %d, \00", align 1
+@strtmp.35 = private unnamed_addr constant [8 x i8] c"y = %d\0A\00", align 1
+@strtmp.36 = private unnamed_addr constant [15 x i8] c"  x && y = %d\0A\00", align 1
+@strtmp.37 = private unnamed_addr constant [15 x i8] c"  x || y = %d\0A\00", align 1
+@strtmp.38 = private unnamed_addr constant [14 x i8] c"  x & y = %d\0A\00", align 1
+@strtmp.39 = private unnamed_addr constant [14 x i8] c"  x | y = %d\0A\00", align 1
+@strtmp.40 = private unnamed_addr constant [14 x i8] c"  x ^ y = %d\0A\00", align 1
+@strtmp.41 = private unnamed_addr constant [15 x i8] c"  x == y = %d\0A\00", align 1
+@strtmp.42 = private unnamed_addr constant [15 x i8] c"  x != y = %d\0A\00", align 1
+@strtmp.43 = private unnamed_addr constant [11 x i8] c"  !x = %d\0A\00", align 1
+@strtmp.44 = private unnamed_addr constant [14 x i8] c"1 << 33 = %d\0A\00", align 1
+@strtmp.45 = private unnamed_addr constant [24 x i8] c"2 + 3 * 4 - 6 / 2 = %d\0A\00", align 1
+@4 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp.46 = private unnamed_addr constant [16 x i8] c"1 + 6 & 3 = %d\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define i32 @b(i1 %value) {
+entry:
+  %value1 = alloca i1, align 1
+  store i1 %value, i1* %value1, align 1
+  %value2 = load i1, i1* %value1, align 1
+  br i1 %value2, label %then, label %else
+
+then:                                             ; preds = %entry
+  ret i32 1
+
+else:                                             ; preds = %entry
+  br label %exit
+
+exit:                                             ; preds = %else
+  ret i32 0
+}
+
+define void @ints(i32 %x, i32 %y) {
+entry:
+  %y2 = alloca i32, align 4
+  %x1 = alloca i32, align 4
+  store i32 %x, i32* %x1, align 4
+  store i32 %y, i32* %y2, align 4
+  %x3 = load i32, i32* %x1, align 4
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([9 x i8], [9 x i8]* @strtmp, i32 0, i32 0), i32 %x3)
+  %y4 = load i32, i32* %y2, align 4
+  %1 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([8 x i8], [8 x i8]* @strtmp.1, i32 0, i32 0), i32 %y4)
+  %x5 = load i32, i32* %x1, align 4
+  %y6 = load i32, i32* %y2, align 4
+  %addtmp = add i32 %x5, %y6
+  %2 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.2, i32 0, i32 0), i32 %addtmp)
+  %x7 = load i32, i32* %x1, align 4
+  %y8 = load i32, i32* %y2, align 4
+  %subtmp = sub i32 %x7, %y8
+  %3 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.3, i32 0, i32 0), i32 %subtmp)
+  %x9 = load i32, i32* %x1, align 4
+  %y10 = load i32, i32* %y2, align 4
+  %multmp = mul i32 %x9, %y10
+  %4 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.4, i32 0, i32 0), i32 %multmp)
+  %x11 = load i32, i32* %x1, align 4
+  %y12 = load i32, i32* %y2, align 4
+  %iszero = icmp eq i32 %y12, 0
+  br i1 %iszero, label %panic, label %ok
+
+panic:                                            ; preds = %entry
+  %5 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @0, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %entry
+  %divtmp = sdiv i32 %x11, %y12
+  %6 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.5, i32 0, i32 0), i32 %divtmp)
+  %x13 = load i32, i32* %x1, align 4
+  %y14 = load i32, i32* %y2, align 4
+  %iszero15 = icmp eq i32 %y14, 0
+  br i1 %iszero15, label %panic16, label %ok17
+
+panic16:                                          ; preds = %ok
+  %7 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @1, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok17:                                             ; preds = %ok
+  %remtmp = srem i32 %x13, %y14
+  %8 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.6, i32 0, i32 0), i32 %remtmp)
+  %x18 = load i32, i32* %x1, align 4
+  %y19 = load i32, i32* %y2, align 4
+  %andtmp = and i32 %x18, %y19
+  %9 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.7, i32 0, i32 0), i32 %andtmp)
+  %x20 = load i32, i32* %x1, align 4
+  %y21 = load i32, i32* %y2, align 4
+  %ortmp = or i32 %x20, %y21
+  %10 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.8, i32 0, i32 0), i32 %ortmp)
+  %x22 = load i32, i32* %x1, align 4
+  %y23 = load i32, i32* %y2, align 4
+  %xortmp = xor i32 %x22, %y23
+  %11 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.9, i32 0, i32 0), i32 %xortmp)
+  %x24 = load i32, i32* %x1, align 4
+  %y25 = load i32, i32* %y2, align 4
+  %shiftcount = and i32 %y25, 31
+  %shltmp = shl i32 %x24, %shiftcount
+  %12 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.10, i32 0, i32 0), i32 %shltmp)
+  %x26 = load i32, i32* %x1, align 4
+  %y27 = load i32, i32* %y2, align 4
+  %shiftcount28 = and i32 %y27, 31
+  %shrtmp = ashr i32 %x26, %shiftcount28
+  %13 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.11, i32 0, i32 0), i32 %shrtmp)
+  %x29 = load i32, i32* %x1, align 4
+  %negtmp = sub i32 0, %x29
+  %14 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.12, i32 0, i32 0), i32 %negtmp)
+  %x30 = load i32, i32* %x1, align 4
+  %y31 = load i32, i32* %y2, align 4
+  %cmptmp = icmp slt i32 %x30, %y31
+  %15 = call i32 @b(i1 %cmptmp)
+  %16 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.13, i32 0, i32 0), i32 %15)
+  %x32 = load i32, i32* %x1, align 4
+  %y33 = load i32, i32* %y2, align 4
+  %cmptmp34 = icmp sle i32 %x32, %y33
+  %17 = call i32 @b(i1 %cmptmp34)
+  %18 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.14, i32 0, i32 0), i32 %17)
+  %x35 = load i32, i32* %x1, align 4
+  %y36 = load i32, i32* %y2, align 4
+  %cmptmp37 = icmp sgt i32 %x35, %y36
+  %19 = call i32 @b(i1 %cmptmp37)
+  %20 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.15, i32 0, i32 0), i32 %19)
+  %x38 = load i32, i32* %x1, align 4
+  %y39 = load i32, i32* %y2, align 4
+  %cmptmp40 = icmp sge i32 %x38, %y39
+  %21 = call i32 @b(i1 %cmptmp40)
+  %22 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.16, i32 0, i32 0), i32 %21)
+  %x41 = load i32, i32* %x1, align 4
+  %y42 = load i32, i32* %y2, align 4
+  %cmptmp43 = icmp eq i32 %x41, %y42
+  %23 = call i32 @b(i1 %cmptmp43)
+  %24 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.17, i32 0, i32 0), i32 %23)
+  %x44 = load i32, i32* %x1, align 4
+  %y45 = load i32, i32* %y2, align 4
+  %cmptmp46 = icmp ne i32 %x44, %y45
+  %25 = call i32 @b(i1 %cmptmp46)
+  %26 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.18, i32 0, i32 0), i32 %25)
+  ret void
+}
+
+define void @floats(double %x, double %y) {
+entry:
+  %y2 = alloca double, align 8
+  %x1 = alloca double, align 8
+  store double %x, double* %x1, align 8
+  store double %y, double* %y2, align 8
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([7 x i8], [7 x i8]* @strtmp.19, i32 0, i32 0), i32 0)
+  %x3 = load double, double* %x1, align 8
+  %y4 = load double, double* %y2, align 8
+  %addtmp = fadd double %x3, %y4
+  %cmptmp = fcmp oeq double %addtmp, 8.500000e+00
+  %1 = call i32 @b(i1 %cmptmp)
+  %2 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([20 x i8], [20 x i8]* @strtmp.20, i32 0, i32 0), i32 %1)
+  %x5 = load double, double* %x1, align 8
+  %y6 = load double, double* %y2, align 8
+  %subtmp = fsub double %x5, %y6
+  %cmptmp7 = fcmp oeq double %subtmp, 4.500000e+00
+  %3 = call i32 @b(i1 %cmptmp7)
+  %4 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([20 x i8], [20 x i8]* @strtmp.21, i32 0, i32 0), i32 %3)
+  %x8 = load double, double* %x1, align 8
+  %y9 = load double, double* %y2, align 8
+  %multmp = fmul double %x8, %y9
+  %cmptmp10 = fcmp oeq double %multmp, 1.300000e+01
+  %5 = call i32 @b(i1 %cmptmp10)
+  %6 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([21 x i8], [21 x i8]* @strtmp.22, i32 0, i32 0), i32 %5)
+  %x11 = load double, double* %x1, align 8
+  %y12 = load double, double* %y2, align 8
+  %iszero = fcmp oeq double %y12, 0.000000e+00
+  br i1 %iszero, label %panic, label %ok
+
+panic:                                            ; preds = %entry
+  %7 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @2, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %entry
+  %divtmp = fdiv double %x11, %y12
+  %cmptmp13 = fcmp oeq double %divtmp, 3.250000e+00
+  %8 = call i32 @b(i1 %cmptmp13)
+  %9 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([21 x i8], [21 x i8]* @strtmp.23, i32 0, i32 0), i32 %8)
+  %x14 = load double, double* %x1, align 8
+  %y15 = load double, double* %y2, align 8
+  %iszero16 = fcmp oeq double %y15, 0.000000e+00
+  br i1 %iszero16, label %panic17, label %ok18
+
+panic17:                                          ; preds = %ok
+  %10 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @3, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok18:                                             ; preds = %ok
+  %remtmp = frem double %x14, %y15
+  %cmptmp19 = fcmp oeq double %remtmp, 5.000000e-01
+  %11 = call i32 @b(i1 %cmptmp19)
+  %12 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([21 x i8], [21 x i8]* @strtmp.24, i32 0, i32 0), i32 %11)
+  %x20 = load double, double* %x1, align 8
+  %negtmp = fneg double %x20
+  %x21 = load double, double* %x1, align 8
+  %subtmp22 = fsub double 0.000000e+00, %x21
+  %cmptmp23 = fcmp oeq double %negtmp, %subtmp22
+  %13 = call i32 @b(i1 %cmptmp23)
+  %14 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([21 x i8], [21 x i8]* @strtmp.25, i32 0, i32 0), i32 %13)
+  %x24 = load double, double* %x1, align 8
+  %y25 = load double, double* %y2, align 8
+  %cmptmp26 = fcmp olt double %x24, %y25
+  %15 = call i32 @b(i1 %cmptmp26)
+  %16 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.26, i32 0, i32 0), i32 %15)
+  %x27 = load double, double* %x1, align 8
+  %y28 = load double, double* %y2, align 8
+  %cmptmp29 = fcmp ole double %x27, %y28
+  %17 = call i32 @b(i1 %cmptmp29)
+  %18 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.27, i32 0, i32 0), i32 %17)
+  %x30 = load double, double* %x1, align 8
+  %y31 = load double, double* %y2, align 8
+  %cmptmp32 = fcmp ogt double %x30, %y31
+  %19 = call i32 @b(i1 %cmptmp32)
+  %20 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.28, i32 0, i32 0), i32 %19)
+  %x33 = load double, double* %x1, align 8
+  %y34 = load double, double* %y2, align 8
+  %cmptmp35 = fcmp oge double %x33, %y34
+  %21 = call i32 @b(i1 %cmptmp35)
+  %22 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.29, i32 0, i32 0), i32 %21)
+  %x36 = load double, double* %x1, align 8
+  %y37 = load double, double* %y2, align 8
+  %cmptmp38 = fcmp oeq double %x36, %y37
+  %23 = call i32 @b(i1 %cmptmp38)
+  %24 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.30, i32 0, i32 0), i32 %23)
+  %x39 = load double, double* %x1, align 8
+  %y40 = load double, double* %y2, align 8
+  %cmptmp41 = fcmp one double %x39, %y40
+  %25 = call i32 @b(i1 %cmptmp41)
+  %26 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.31, i32 0, i32 0), i32 %25)
+  %x42 = load double, double* %x1, align 8
+  %x43 = load double, double* %x1, align 8
+  %cmptmp44 = fcmp ole double %x42, %x43
+  %27 = call i32 @b(i1 %cmptmp44)
+  %28 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.32, i32 0, i32 0), i32 %27)
+  %x45 = load double, double* %x1, align 8
+  %x46 = load double, double* %x1, align 8
+  %cmptmp47 = fcmp oge double %x45, %x46
+  %29 = call i32 @b(i1 %cmptmp47)
+  %30 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.33, i32 0, i32 0), i32 %29)
+  ret void
+}
+
+define void @bools(i1 %x, i1 %y) {
+entry:
+  %y2 = alloca i1, align 1
+  %x1 = alloca i1, align 1
+  store i1 %x, i1* %x1, align 1
+  store i1 %y, i1* %y2, align 1
+  %x3 = load i1, i1* %x1, align 1
+  %0 = call i32 @b(i1 %x3)
+  %1 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([9 x i8], [9 x i8]* @strtmp.34, i32 0, i32 0), i32 %0)
+  %y4 = load i1, i1* %y2, align 1
+  %2 = call i32 @b(i1 %y4)
+  %3 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([8 x i8], [8 x i8]* @strtmp.35, i32 0, i32 0), i32 %2)
+  %x5 = load i1, i1* %x1, align 1
+  br i1 %x5, label %logicrhs, label %logicexit
+
+logicrhs:                                         ; preds = %entry
+  %y6 = load i1, i1* %y2, align 1
+  br label %logicexit
+
+logicexit:                                        ; preds = %logicrhs, %entry
+  %logictmp = phi i1 [ false, %entry ], [ %y6, %logicrhs ]
+  %4 = call i32 @b(i1 %logictmp)
+  %5 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.36, i32 0, i32 0), i32 %4)
+  %x7 = load i1, i1* %x1, align 1
+  br i1 %x7, label %logicexit9, label %logicrhs8
+
+logicrhs8:                                        ; preds = %logicexit
+  %y10 = load i1, i1* %y2, align 1
+  br label %logicexit9
+
+logicexit9:                                       ; preds = %logicrhs8, %logicexit
+  %logictmp11 = phi i1 [ true, %logicexit ], [ %y10, %logicrhs8 ]
+  %6 = call i32 @b(i1 %logictmp11)
+  %7 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.37, i32 0, i32 0), i32 %6)
+  %x12 = load i1, i1* %x1, align 1
+  %y13 = load i1, i1* %y2, align 1
+  %andtmp = and i1 %x12, %y13
+  %8 = call i32 @b(i1 %andtmp)
+  %9 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.38, i32 0, i32 0), i32 %8)
+  %x14 = load i1, i1* %x1, align 1
+  %y15 = load i1, i1* %y2, align 1
+  %ortmp = or i1 %x14, %y15
+  %10 = call i32 @b(i1 %ortmp)
+  %11 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.39, i32 0, i32 0), i32 %10)
+  %x16 = load i1, i1* %x1, align 1
+  %y17 = load i1, i1* %y2, align 1
+  %xortmp = xor i1 %x16, %y17
+  %12 = call i32 @b(i1 %xortmp)
+  %13 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.40, i32 0, i32 0), i32 %12)
+  %x18 = load i1, i1* %x1, align 1
+  %y19 = load i1, i1* %y2, align 1
+  %cmptmp = icmp eq i1 %x18, %y19
+  %14 = call i32 @b(i1 %cmptmp)
+  %15 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.41, i32 0, i32 0), i32 %14)
+  %x20 = load i1, i1* %x1, align 1
+  %y21 = load i1, i1* %y2, align 1
+  %cmptmp22 = icmp ne i1 %x20, %y21
+  %16 = call i32 @b(i1 %cmptmp22)
+  %17 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.42, i32 0, i32 0), i32 %16)
+  %x23 = load i1, i1* %x1, align 1
+  %nottmp = xor i1 %x23, true
+  %18 = call i32 @b(i1 %nottmp)
+  %19 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.43, i32 0, i32 0), i32 %18)
+  ret void
+}
+
+define i32 @main() {
+entry:
+  call void @ints(i32 7, i32 3)
+  call void @ints(i32 -7, i32 2)
+  call void @ints(i32 3, i32 3)
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.44, i32 0, i32 0), i32 2)
+  br i1 false, label %panic, label %ok
+
+panic:                                            ; preds = %entry
+  %1 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @4, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %entry
+  %2 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([24 x i8], [24 x i8]* @strtmp.45, i32 0, i32 0), i32 11)
+  %3 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([16 x i8], [16 x i8]* @strtmp.46, i32 0, i32 0), i32 3)
+  call void @floats(double 6.500000e+00, double 2.000000e+00)
+  call void @bools(i1 true, i1 false)
+  call void @bools(i1 true, i1 true)
+  call void @bools(i1 false, i1 false)
+  ret i32 0
+}
+
+declare i32 @puts(i8*)
+
+declare void @exit(i32)
@@ -0,0 +1,177 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+%struct.Vec2 = type { double, double }
+
+@strtmp = private unnamed_addr constant [2 x i8] c"!\00", align 1
+@strtmp.1 = private unnamed_addr constant [7 x i8] c"%f %f\0A\00", align 1
+@strtmp.2 = private unnamed_addr constant [7 x i8] c"%f %f\0A\00", align 1
+@strtmp.3 = private unnamed_addr constant [7 x i8] c"%f %f\0A\00", align 1
+@strtmp.4 = private unnamed_addr constant [4 x i8] c"wow\00", align 1
+@strtmp.5 = private unnamed_addr constant [7 x i8] c"%f %f\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define %struct.Vec2 @"binary_+(Vec2,Vec2)"(%struct.Vec2 %a, %struct.Vec2 %b) {
+entry:
+  %b2 = alloca %struct.Vec2, align 8
+  %a1 = alloca %struct.Vec2, align 8
+  store %struct.Vec2 %a, %struct.Vec2* %a1, align 8
+  store %struct.Vec2 %b, %struct.Vec2* %b2, align 8
+  %a3 = load %struct.Vec2, %struct.Vec2* %a1, align 8
+  %x = extractvalue %struct.Vec2 %a3, 0
+  %b4 = load %struct.Vec2, %struct.Vec2* %b2, align 8
+  %x5 = extractvalue %struct.Vec2 %b4, 0
+  %addtmp = fadd double %x, %x5
+  %0 = insertvalue %struct.Vec2 undef, double %addtmp, 0
+  %a6 = load %struct.Vec2, %struct.Vec2* %a1, align 8
+  %y = extractvalue %struct.Vec2 %a6, 1
+  %b7 = load %struct.Vec2, %struct.Vec2* %b2, align 8
+  %y8 = extractvalue %struct.Vec2 %b7, 1
+  %addtmp9 = fadd double %y, %y8
+  %1 = insertvalue %struct.Vec2 %0, double %addtmp9, 1
+  ret %struct.Vec2 %1
+}
+
+define %struct.Vec2 @"binary_*(Vec2,float)"(%struct.Vec2 %a, double %k) {
+entry:
+  %k2 = alloca double, align 8
+  %a1 = alloca %struct.Vec2, align 8
+  store %struct.Vec2 %a, %struct.Vec2* %a1, align 8
+  store double %k, double* %k2, align 8
+  %a3 = load %struct.Vec2, %struct.Vec2* %a1, align 8
+  %x = extractvalue %struct.Vec2 %a3, 0
+  %k4 = load double, double* %k2, align 8
+  %multmp = fmul double %x, %k4
+  %0 = insertvalue %struct.Vec2 undef, double %multmp, 0
+  %a5 = load %struct.Vec2, %struct.Vec2* %a1, align 8
+  %y = extractvalue %struct.Vec2 %a5, 1
+  %k6 = load double, double* %k2, align 8
+  %multmp7 = fmul double %y, %k6
+  %1 = insertvalue %struct.Vec2 %0, double %multmp7, 1
+  ret %struct.Vec2 %1
+}
+
+define double @"binary_*(Vec2,Vec2)"(%struct.Vec2 %a, %struct.Vec2 %b) {
+entry:
+  %b2 = alloca %struct.Vec2, align 8
+  %a1 = alloca %struct.Vec2, align 8
+  store %struct.Vec2 %a, %struct.Vec2* %a1, align 8
+  store %struct.Vec2 %b, %struct.Vec2* %b2, align 8
+  %a3 = load %struct.Vec2, %struct.Vec2* %a1, align 8
+  %x = extractvalue %struct.Vec2 %a3, 0
+  %b4 = load %struct.Vec2, %struct.Vec2* %b2, align 8
+  %x5 = extractvalue %struct.Vec2 %b4, 0
+  %multmp = fmul double %x, %x5
+  %a6 = load %struct.Vec2, %struct.Vec2* %a1, align 8
+  %y = extractvalue %struct.Vec2 %a6, 1
+  %b7 = load %struct.Vec2, %struct.Vec2* %b2, align 8
+  %y8 = extractvalue %struct.Vec2 %b7, 1
+  %multmp9 = fmul double %y, %y8
+  %addtmp = fadd double %multmp, %multmp9
+  ret double %addtmp
+}
+
+define i8* @"binary_+(str,float)"(i8* %s, double %n) {
+entry:
+  %n2 = alloca double, align 8
+  %s1 = alloca i8*, align 8
+  store i8* %s, i8** %s1, align 8
+  store double %n, double* %n2, align 8
+  %s3 = load i8*, i8** %s1, align 8
+  %concattmp = call i8* @novum.strconcat(i8* %s3, i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp, i32 0, i32 0))
+  ret i8* %concattmp
+}
+
+define %struct.Vec2 @"unary_-(Vec2)"(%struct.Vec2 %v) {
+entry:
+  %v1 = alloca %struct.Vec2, align 8
+  store %struct.Vec2 %v, %struct.Vec2* %v1, align 8
+  %v2 = load %struct.Vec2, %struct.Vec2* %v1, align 8
+  %x = extractvalue %struct.Vec2 %v2, 0
+  %negtmp = fneg double %x
+  %0 = insertvalue %struct.Vec2 undef, double %negtmp, 0
+  %v3 = load %struct.Vec2, %struct.Vec2* %v1, align 8
+  %y = extractvalue %struct.Vec2 %v3, 1
+  %negtmp4 = fneg double %y
+  %1 = insertvalue %struct.Vec2 %0, double %negtmp4, 1
+  ret %struct.Vec2 %1
+}
+
+define i1 @"unary_-(bool)"(i1 %b) {
+entry:
+  %b1 = alloca i1, align 1
+  store i1 %b, i1* %b1, align 1
+  %b2 = load i1, i1* %b1, align 1
+  %nottmp = xor i1 %b2, true
+  ret i1 %nottmp
+}
+
+define i32 @main() {
+entry:
+  %d = alloca %struct.Vec2, align 8
+  %c = alloca %struct.Vec2, align 8
+  %b = alloca %struct.Vec2, align 8
+  %a = alloca %struct.Vec2, align 8
+  store %struct.Vec2 { double 1.000000e+00, double 2.000000e+00 }, %struct.Vec2* %a, align 8
+  store %struct.Vec2 { double 3.000000e+00, double 4.000000e+00 }, %struct.Vec2* %b, align 8
+  %a1 = load %struct.Vec2, %struct.Vec2* %a, align 8
+  %b2 = load %struct.Vec2, %struct.Vec2* %b, align 8
+  %0 = call %struct.Vec2 @"binary_*(Vec2,float)"(%struct.Vec2 %b2, double 2.000000e+00)
+  %1 = call %struct.Vec2 @"binary_+(Vec2,Vec2)"(%struct.Vec2 %a1, %struct.Vec2 %0)
+  store %struct.Vec2 %1, %struct.Vec2* %c, align 8
+  %c3 = load %struct.Vec2, %struct.Vec2* %c, align 8
+  %x = extractvalue %struct.Vec2 %c3, 0
+  %c4 = load %struct.Vec2, %struct.Vec2* %c, align 8
+  %y = extractvalue %struct.Vec2 %c4, 1
+  %2 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([7 x i8], [7 x i8]* @strtmp.1, i32 0, i32 0), double %x, double %y)
+  %a5 = load %struct.Vec2, %struct.Vec2* %a, align 8
+  %b6 = load %struct.Vec2, %struct.Vec2* %b, align 8
+  %3 = call double @"binary_*(Vec2,Vec2)"(%struct.Vec2 %a5, %struct.Vec2 %b6)
+  %4 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([7 x i8], [7 x i8]* @strtmp.2, i32 0, i32 0), double %3, double 7.000000e+00)
+  %a7 = load %struct.Vec2, %struct.Vec2* %a, align 8
+  %b8 = load %struct.Vec2, %struct.Vec2* %b, align 8
+  %5 = call %struct.Vec2 @"binary_+(Vec2,Vec2)"(%struct.Vec2 %a7, %struct.Vec2 %b8)
+  %6 = call %struct.Vec2 @"unary_-(Vec2)"(%struct.Vec2 %5)
+  store %struct.Vec2 %6, %struct.Vec2* %d, align 8
+  %d9 = load %struct.Vec2, %struct.Vec2* %d, align 8
+  %x10 = extractvalue %struct.Vec2 %d9, 0
+  %d11 = load %struct.Vec2, %struct.Vec2* %d, align 8
+  %y12 = extractvalue %struct.Vec2 %d11, 1
+  %7 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([7 x i8], [7 x i8]* @strtmp.3, i32 0, i32 0), double %x10, double %y12)
+  %8 = call i1 @"unary_-(bool)"(i1 false)
+  br i1 %8, label %then, label %else
+
+then:                                             ; preds = %entry
+  %9 = call i8* @"binary_+(str,float)"(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.4, i32 0, i32 0), double 1.500000e+00)
+  %concattmp = call i8* @novum.strconcat(i8* %9, i8* getelementptr inbounds ([7 x i8], [7 x i8]* @strtmp.5, i32 0, i32 0))
+  %10 = call i32 (i8*, ...) @printf(i8* %concattmp, double 0.000000e+00, double 0.000000e+00)
+  br label %exit
+
+else:                                             ; preds = %entry
+  br label %exit
+
+exit:                                             ; preds = %else, %then
+  ret i32 0
+}
+
+declare i64 @strlen(i8*)
+
+declare i8* @malloc(i64)
+
+declare i8* @memcpy(i8*, i8*, i64)
+
+define internal i8* @novum.strconcat(i8* %a, i8* %b) {
+entry:
+  %lena = call i64 @strlen(i8* %a)
+  %lenb = call i64 @strlen(i8* %b)
+  %lenbnull = add i64 %lenb, 1
+  %size = add i64 %lena, %lenbnull
+  %buffer = call i8* @malloc(i64 %size)
+  %0 = call i8* @memcpy(i8* %buffer, i8* %a, i64 %lena)
+  %tail = getelementptr inbounds i8, i8* %buffer, i64 %lena
+  %1 = call i8* @memcpy(i8* %tail, i8* %b, i64 %lenbnull)
+  ret i8* %buffer
+}
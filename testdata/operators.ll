@@ -0,0 +1,83 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@strtmp = private unnamed_addr constant [4 x i8] c"%d\0A\00", align 1
+@0 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp.1 = private unnamed_addr constant [5 x i8] c"xor\0A\00", align 1
+@strtmp.2 = private unnamed_addr constant [8 x i8] c"floats\0A\00", align 1
+@strtmp.3 = private unnamed_addr constant [4 x i8] c"%d\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+declare void @exit(i32)
+
+define i1 @"binary_^^"(i1 %a, i1 %b) {
+entry:
+  %b2 = alloca i1, align 1
+  %a1 = alloca i1, align 1
+  store i1 %a, i1* %a1, align 1
+  store i1 %b, i1* %b2, align 1
+  %a3 = load i1, i1* %a1, align 1
+  %b4 = load i1, i1* %b2, align 1
+  %cmptmp = icmp ne i1 %a3, %b4
+  ret i1 %cmptmp
+}
+
+define i32 @"unary_~"(i32 %a) {
+entry:
+  %a1 = alloca i32, align 4
+  store i32 %a, i32* %a1, align 4
+  %a2 = load i32, i32* %a1, align 4
+  %a3 = load i32, i32* %a1, align 4
+  %multmp = mul i32 %a2, %a3
+  ret i32 %multmp
+}
+
+define i32 @main() {
+entry:
+  br i1 false, label %panic, label %ok
+
+panic:                                            ; preds = %entry
+  %0 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @0, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %entry
+  %1 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp, i32 0, i32 0), i32 44)
+  %2 = call i1 @"binary_^^"(i1 true, i1 false)
+  br i1 %2, label %then, label %else
+
+then:                                             ; preds = %ok
+  %3 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([5 x i8], [5 x i8]* @strtmp.1, i32 0, i32 0), i32 0)
+  br label %exit
+
+else:                                             ; preds = %ok
+  br label %exit
+
+exit:                                             ; preds = %else, %then
+  br i1 true, label %logicrhs, label %logicexit
+
+logicrhs:                                         ; preds = %exit
+  br label %logicexit
+
+logicexit:                                        ; preds = %logicrhs, %exit
+  %logictmp = phi i1 [ false, %exit ], [ true, %logicrhs ]
+  br i1 %logictmp, label %then2, label %else3
+
+then2:                                            ; preds = %logicexit
+  %4 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([8 x i8], [8 x i8]* @strtmp.2, i32 0, i32 0), i32 0)
+  br label %exit1
+
+else3:                                            ; preds = %logicexit
+  br label %exit1
+
+exit1:                                            ; preds = %else3, %then2
+  %5 = call i32 @"unary_~"(i32 5)
+  %6 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.3, i32 0, i32 0), i32 %5)
+  call void @exit(i32 3)
+  ret i32 0
+}
+
+declare i32 @puts(i8*)
@@ -0,0 +1,146 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+%struct.Point = type { i32, i32 }
+
+@strtmp = private unnamed_addr constant [10 x i8] c"int %lld\0A\00", align 1
+@strtmp.1 = private unnamed_addr constant [5 x i8] c"str \00", align 1
+@strtmp.2 = private unnamed_addr constant [2 x i8] c"\0A\00", align 1
+@strtmp.3 = private unnamed_addr constant [10 x i8] c"i64 %lld\0A\00", align 1
+@strtmp.4 = private unnamed_addr constant [4 x i8] c"one\00", align 1
+@strtmp.5 = private unnamed_addr constant [4 x i8] c"one\00", align 1
+@strtmp.6 = private unnamed_addr constant [4 x i8] c"two\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define i32 @"show(int)"(i32 %n) {
+entry:
+  %n1 = alloca i32, align 4
+  store i32 %n, i32* %n1, align 4
+  %n2 = load i32, i32* %n1, align 4
+  %casttmp = sext i32 %n2 to i64
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([10 x i8], [10 x i8]* @strtmp, i32 0, i32 0), i64 %casttmp)
+  ret i32 %0
+}
+
+define i32 @"show(str)"(i8* %s) {
+entry:
+  %s1 = alloca i8*, align 8
+  store i8* %s, i8** %s1, align 8
+  %s2 = load i8*, i8** %s1, align 8
+  %concattmp = call i8* @novum.strconcat(i8* getelementptr inbounds ([5 x i8], [5 x i8]* @strtmp.1, i32 0, i32 0), i8* %s2)
+  %concattmp3 = call i8* @novum.strconcat(i8* %concattmp, i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.2, i32 0, i32 0))
+  %0 = call i32 (i8*, ...) @printf(i8* %concattmp3, i64 0)
+  ret i32 %0
+}
+
+define void @"show(Point)"(%struct.Point %p) {
+entry:
+  %p1 = alloca %struct.Point, align 8
+  store %struct.Point %p, %struct.Point* %p1, align 4
+  %p2 = load %struct.Point, %struct.Point* %p1, align 4
+  %x = extractvalue %struct.Point %p2, 0
+  %0 = call i32 @"show(int)"(i32 %x)
+  %p3 = load %struct.Point, %struct.Point* %p1, align 4
+  %y = extractvalue %struct.Point %p3, 1
+  %1 = call i32 @"show(int)"(i32 %y)
+  ret void
+}
+
+define void @"show(str,str)"(i8* %a, i8* %b) {
+entry:
+  %b2 = alloca i8*, align 8
+  %a1 = alloca i8*, align 8
+  store i8* %a, i8** %a1, align 8
+  store i8* %b, i8** %b2, align 8
+  %a3 = load i8*, i8** %a1, align 8
+  %b4 = load i8*, i8** %b2, align 8
+  %concattmp = call i8* @novum.strconcat(i8* %a3, i8* %b4)
+  %0 = call i32 @"show(str)"(i8* %concattmp)
+  ret void
+}
+
+define i32 @"show(i64)"(i64 %n) {
+entry:
+  %n1 = alloca i64, align 8
+  store i64 %n, i64* %n1, align 8
+  %n2 = load i64, i64* %n1, align 8
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([10 x i8], [10 x i8]* @strtmp.3, i32 0, i32 0), i64 %n2)
+  ret i32 %0
+}
+
+define %struct.Point @"Point.moved(int)"(%struct.Point %p, i32 %by) {
+entry:
+  %by2 = alloca i32, align 4
+  %p1 = alloca %struct.Point, align 8
+  store %struct.Point %p, %struct.Point* %p1, align 4
+  store i32 %by, i32* %by2, align 4
+  %p3 = load %struct.Point, %struct.Point* %p1, align 4
+  %x = extractvalue %struct.Point %p3, 0
+  %by4 = load i32, i32* %by2, align 4
+  %addtmp = add i32 %x, %by4
+  %0 = insertvalue %struct.Point undef, i32 %addtmp, 0
+  %p5 = load %struct.Point, %struct.Point* %p1, align 4
+  %y = extractvalue %struct.Point %p5, 1
+  %by6 = load i32, i32* %by2, align 4
+  %addtmp7 = add i32 %y, %by6
+  %1 = insertvalue %struct.Point %0, i32 %addtmp7, 1
+  ret %struct.Point %1
+}
+
+define %struct.Point @"Point.moved(int,int)"(%struct.Point %p, i32 %x, i32 %y) {
+entry:
+  %y3 = alloca i32, align 4
+  %x2 = alloca i32, align 4
+  %p1 = alloca %struct.Point, align 8
+  store %struct.Point %p, %struct.Point* %p1, align 4
+  store i32 %x, i32* %x2, align 4
+  store i32 %y, i32* %y3, align 4
+  %p4 = load %struct.Point, %struct.Point* %p1, align 4
+  %x5 = extractvalue %struct.Point %p4, 0
+  %x6 = load i32, i32* %x2, align 4
+  %addtmp = add i32 %x5, %x6
+  %0 = insertvalue %struct.Point undef, i32 %addtmp, 0
+  %p7 = load %struct.Point, %struct.Point* %p1, align 4
+  %y8 = extractvalue %struct.Point %p7, 1
+  %y9 = load i32, i32* %y3, align 4
+  %addtmp10 = add i32 %y8, %y9
+  %1 = insertvalue %struct.Point %0, i32 %addtmp10, 1
+  ret %struct.Point %1
+}
+
+define i32 @main() {
+entry:
+  %wide = alloca i64, align 8
+  %0 = call i32 @"show(int)"(i32 1)
+  %1 = call i32 @"show(str)"(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.4, i32 0, i32 0))
+  call void @"show(str,str)"(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.5, i32 0, i32 0), i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.6, i32 0, i32 0))
+  store i64 3, i64* %wide, align 8
+  %wide1 = load i64, i64* %wide, align 8
+  %2 = call i32 @"show(i64)"(i64 %wide1)
+  %3 = call %struct.Point @"Point.moved(int)"(%struct.Point { i32 1, i32 2 }, i32 1)
+  %4 = call %struct.Point @"Point.moved(int,int)"(%struct.Point %3, i32 0, i32 10)
+  call void @"show(Point)"(%struct.Point %4)
+  ret i32 0
+}
+
+declare i64 @strlen(i8*)
+
+declare i8* @malloc(i64)
+
+declare i8* @memcpy(i8*, i8*, i64)
+
+define internal i8* @novum.strconcat(i8* %a, i8* %b) {
+entry:
+  %lena = call i64 @strlen(i8* %a)
+  %lenb = call i64 @strlen(i8* %b)
+  %lenbnull = add i64 %lenb, 1
+  %size = add i64 %lena, %lenbnull
+  %buffer = call i8* @malloc(i64 %size)
+  %0 = call i8* @memcpy(i8* %buffer, i8* %a, i64 %lena)
+  %tail = getelementptr inbounds i8, i8* %buffer, i64 %lena
+  %1 = call i8* @memcpy(i8* %tail, i8* %b, i64 %lenbnull)
+  ret i8* %buffer
+}
@@ -0,0 +1,247 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@0 = private unnamed_addr constant [39 x i8] c"Panic: step of the range is equal to 0\00", align 1
+@strtmp = private unnamed_addr constant [7 x i8] c"up %d\0A\00", align 1
+@strtmp.1 = private unnamed_addr constant [14 x i8] c"inclusive %d\0A\00", align 1
+@1 = private unnamed_addr constant [39 x i8] c"Panic: step of the range is equal to 0\00", align 1
+@strtmp.2 = private unnamed_addr constant [5 x i8] c"%d: \00", align 1
+@strtmp.3 = private unnamed_addr constant [9 x i8] c"down %d\0A\00", align 1
+@strtmp.4 = private unnamed_addr constant [10 x i8] c"empty %d\0A\00", align 1
+@strtmp.5 = private unnamed_addr constant [6 x i8] c"hello\00", align 1
+@strtmp.6 = private unnamed_addr constant [10 x i8] c"chars %d\0A\00", align 1
+@strtmp.7 = private unnamed_addr constant [8 x i8] c"sum %d\0A\00", align 1
+@strtmp.8 = private unnamed_addr constant [12 x i8] c"odd sum %d\0A\00", align 1
+@strtmp.9 = private unnamed_addr constant [14 x i8] c"zero step %d\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define i32 @sum(i32 %from, i32 %to, i32 %step) {
+entry:
+  %n = alloca i32, align 4
+  %ind = alloca i32, align 4
+  %total = alloca i32, align 4
+  %step3 = alloca i32, align 4
+  %to2 = alloca i32, align 4
+  %from1 = alloca i32, align 4
+  store i32 %from, i32* %from1, align 4
+  store i32 %to, i32* %to2, align 4
+  store i32 %step, i32* %step3, align 4
+  store i32 0, i32* %total, align 4
+  store i32 0, i32* %ind, align 4
+  %from4 = load i32, i32* %from1, align 4
+  store i32 %from4, i32* %n, align 4
+  %to5 = load i32, i32* %to2, align 4
+  %step6 = load i32, i32* %step3, align 4
+  %zerostep = icmp eq i32 %step6, 0
+  br i1 %zerostep, label %panic, label %ok
+
+loopheader:                                       ; preds = %looplatch, %ok
+  %n7 = load i32, i32* %n, align 4
+  %countsup = icmp sgt i32 %step6, 0
+  %beforeend = icmp sle i32 %n7, %to5
+  %afterend = icmp sge i32 %n7, %to5
+  %loopcond = select i1 %countsup, i1 %beforeend, i1 %afterend
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %total8 = load i32, i32* %total, align 4
+  %n9 = load i32, i32* %n, align 4
+  %addtmp = add i32 %total8, %n9
+  store i32 %addtmp, i32* %total, align 4
+  br label %looplatch
+
+looplatch:                                        ; preds = %loop
+  %n10 = load i32, i32* %n, align 4
+  %next = add i32 %n10, %step6
+  store i32 %next, i32* %n, align 4
+  %ind11 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind11, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  %total12 = load i32, i32* %total, align 4
+  ret i32 %total12
+
+panic:                                            ; preds = %entry
+  %0 = call i32 @puts(i8* getelementptr inbounds ([39 x i8], [39 x i8]* @0, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %entry
+  br label %loopheader
+}
+
+define i32 @main() {
+entry:
+  %c = alloca i8, align 1
+  %ind54 = alloca i32, align 4
+  %chars = alloca i32, align 4
+  %n40 = alloca i32, align 4
+  %ind39 = alloca i32, align 4
+  %n = alloca i32, align 4
+  %ind24 = alloca i32, align 4
+  %i10 = alloca i32, align 4
+  %ind9 = alloca i32, align 4
+  %i = alloca i32, align 4
+  %ind = alloca i32, align 4
+  store i32 0, i32* %ind, align 4
+  store i32 0, i32* %i, align 4
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %i1 = load i32, i32* %i, align 4
+  %beforeend = icmp slt i32 %i1, 3
+  %afterend = icmp sgt i32 %i1, 3
+  %loopcond = select i1 true, i1 %beforeend, i1 %afterend
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %i2 = load i32, i32* %i, align 4
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([7 x i8], [7 x i8]* @strtmp, i32 0, i32 0), i32 %i2)
+  br label %looplatch
+
+looplatch:                                        ; preds = %loop
+  %i3 = load i32, i32* %i, align 4
+  %next = add i32 %i3, 1
+  store i32 %next, i32* %i, align 4
+  %ind4 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind4, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  store i32 0, i32* %ind9, align 4
+  store i32 0, i32* %i10, align 4
+  br label %loopheader5
+
+loopheader5:                                      ; preds = %looplatch7, %exitloop
+  %i11 = load i32, i32* %i10, align 4
+  %beforeend12 = icmp sle i32 %i11, 3
+  %afterend13 = icmp sge i32 %i11, 3
+  %loopcond14 = select i1 true, i1 %beforeend12, i1 %afterend13
+  br i1 %loopcond14, label %loop6, label %exitloop8
+
+loop6:                                            ; preds = %loopheader5
+  %i15 = load i32, i32* %i10, align 4
+  %1 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.1, i32 0, i32 0), i32 %i15)
+  br label %looplatch7
+
+looplatch7:                                       ; preds = %loop6
+  %i16 = load i32, i32* %i10, align 4
+  %next17 = add i32 %i16, 1
+  store i32 %next17, i32* %i10, align 4
+  %ind18 = load i32, i32* %ind9, align 4
+  %nextind19 = add i32 %ind18, 1
+  store i32 %nextind19, i32* %ind9, align 4
+  br label %loopheader5
+
+exitloop8:                                        ; preds = %loopheader5
+  store i32 0, i32* %ind24, align 4
+  store i32 10, i32* %n, align 4
+  br i1 false, label %panic, label %ok
+
+loopheader20:                                     ; preds = %looplatch22, %ok
+  %n25 = load i32, i32* %n, align 4
+  %beforeend26 = icmp slt i32 %n25, 0
+  %afterend27 = icmp sgt i32 %n25, 0
+  %loopcond28 = select i1 false, i1 %beforeend26, i1 %afterend27
+  br i1 %loopcond28, label %loop21, label %exitloop23
+
+loop21:                                           ; preds = %loopheader20
+  %i29 = load i32, i32* %ind24, align 4
+  %2 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([5 x i8], [5 x i8]* @strtmp.2, i32 0, i32 0), i32 %i29)
+  %n30 = load i32, i32* %n, align 4
+  %3 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([9 x i8], [9 x i8]* @strtmp.3, i32 0, i32 0), i32 %n30)
+  br label %looplatch22
+
+looplatch22:                                      ; preds = %loop21
+  %n31 = load i32, i32* %n, align 4
+  %next32 = add i32 %n31, -3
+  store i32 %next32, i32* %n, align 4
+  %ind33 = load i32, i32* %ind24, align 4
+  %nextind34 = add i32 %ind33, 1
+  store i32 %nextind34, i32* %ind24, align 4
+  br label %loopheader20
+
+exitloop23:                                       ; preds = %loopheader20
+  store i32 0, i32* %ind39, align 4
+  store i32 5, i32* %n40, align 4
+  br label %loopheader35
+
+panic:                                            ; preds = %exitloop8
+  %4 = call i32 @puts(i8* getelementptr inbounds ([39 x i8], [39 x i8]* @1, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %exitloop8
+  br label %loopheader20
+
+loopheader35:                                     ; preds = %looplatch37, %exitloop23
+  %n41 = load i32, i32* %n40, align 4
+  %beforeend42 = icmp slt i32 %n41, 5
+  %afterend43 = icmp sgt i32 %n41, 5
+  %loopcond44 = select i1 true, i1 %beforeend42, i1 %afterend43
+  br i1 %loopcond44, label %loop36, label %exitloop38
+
+loop36:                                           ; preds = %loopheader35
+  %n45 = load i32, i32* %n40, align 4
+  %5 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([10 x i8], [10 x i8]* @strtmp.4, i32 0, i32 0), i32 %n45)
+  br label %looplatch37
+
+looplatch37:                                      ; preds = %loop36
+  %n46 = load i32, i32* %n40, align 4
+  %next47 = add i32 %n46, 1
+  store i32 %next47, i32* %n40, align 4
+  %ind48 = load i32, i32* %ind39, align 4
+  %nextind49 = add i32 %ind48, 1
+  store i32 %nextind49, i32* %ind39, align 4
+  br label %loopheader35
+
+exitloop38:                                       ; preds = %loopheader35
+  store i32 0, i32* %chars, align 4
+  store i32 0, i32* %ind54, align 4
+  %len = call i64 @strlen(i8* getelementptr inbounds ([6 x i8], [6 x i8]* @strtmp.5, i32 0, i32 0))
+  %len55 = trunc i64 %len to i32
+  br label %loopheader50
+
+loopheader50:                                     ; preds = %looplatch52, %exitloop38
+  %ind56 = load i32, i32* %ind54, align 4
+  %loopcond57 = icmp slt i32 %ind56, %len55
+  br i1 %loopcond57, label %loop51, label %exitloop53
+
+loop51:                                           ; preds = %loopheader50
+  %6 = getelementptr inbounds i8, i8* getelementptr inbounds ([6 x i8], [6 x i8]* @strtmp.5, i32 0, i32 0), i32 %ind56
+  %char = load i8, i8* %6, align 1
+  store i8 %char, i8* %c, align 1
+  %chars58 = load i32, i32* %chars, align 4
+  %addtmp = add i32 %chars58, 1
+  store i32 %addtmp, i32* %chars, align 4
+  br label %looplatch52
+
+looplatch52:                                      ; preds = %loop51
+  %ind59 = load i32, i32* %ind54, align 4
+  %nextind60 = add i32 %ind59, 1
+  store i32 %nextind60, i32* %ind54, align 4
+  br label %loopheader50
+
+exitloop53:                                       ; preds = %loopheader50
+  %chars61 = load i32, i32* %chars, align 4
+  %7 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([10 x i8], [10 x i8]* @strtmp.6, i32 0, i32 0), i32 %chars61)
+  %8 = call i32 @sum(i32 1, i32 10, i32 1)
+  %9 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([8 x i8], [8 x i8]* @strtmp.7, i32 0, i32 0), i32 %8)
+  %10 = call i32 @sum(i32 1, i32 10, i32 2)
+  %11 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([12 x i8], [12 x i8]* @strtmp.8, i32 0, i32 0), i32 %10)
+  %12 = call i32 @sum(i32 1, i32 10, i32 0)
+  %13 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.9, i32 0, i32 0), i32 %12)
+  ret i32 0
+}
+
+declare i32 @puts(i8*)
+
+declare void @exit(i32)
+
+declare i64 @strlen(i8*)
@@ -0,0 +1,109 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@strtmp = private unnamed_addr constant [10 x i8] c"%s: true\0A\00", align 1
+@strtmp.1 = private unnamed_addr constant [11 x i8] c"%s: false\0A\00", align 1
+@strtmp.2 = private unnamed_addr constant [2 x i8] c"a\00", align 1
+@strtmp.3 = private unnamed_addr constant [2 x i8] c"b\00", align 1
+@strtmp.4 = private unnamed_addr constant [11 x i8] c"ab == 'ab'\00", align 1
+@strtmp.5 = private unnamed_addr constant [3 x i8] c"ab\00", align 1
+@strtmp.6 = private unnamed_addr constant [11 x i8] c"ab != 'ab'\00", align 1
+@strtmp.7 = private unnamed_addr constant [3 x i8] c"ab\00", align 1
+@strtmp.8 = private unnamed_addr constant [14 x i8] c"'abc' < 'abd'\00", align 1
+@strtmp.9 = private unnamed_addr constant [4 x i8] c"abc\00", align 1
+@strtmp.10 = private unnamed_addr constant [4 x i8] c"abd\00", align 1
+@strtmp.11 = private unnamed_addr constant [11 x i8] c"'b' <= 'a'\00", align 1
+@strtmp.12 = private unnamed_addr constant [2 x i8] c"b\00", align 1
+@strtmp.13 = private unnamed_addr constant [2 x i8] c"a\00", align 1
+@strtmp.14 = private unnamed_addr constant [10 x i8] c"'b' > 'a'\00", align 1
+@strtmp.15 = private unnamed_addr constant [2 x i8] c"b\00", align 1
+@strtmp.16 = private unnamed_addr constant [2 x i8] c"a\00", align 1
+@strtmp.17 = private unnamed_addr constant [11 x i8] c"'a' >= 'a'\00", align 1
+@strtmp.18 = private unnamed_addr constant [2 x i8] c"a\00", align 1
+@strtmp.19 = private unnamed_addr constant [2 x i8] c"a\00", align 1
+@strtmp.20 = private unnamed_addr constant [20 x i8] c"identical(ab, 'ab')\00", align 1
+@strtmp.21 = private unnamed_addr constant [3 x i8] c"ab\00", align 1
+@strtmp.22 = private unnamed_addr constant [18 x i8] c"identical(ab, ab)\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define void @show(i8* %what, i1 %result) {
+entry:
+  %result2 = alloca i1, align 1
+  %what1 = alloca i8*, align 8
+  store i8* %what, i8** %what1, align 8
+  store i1 %result, i1* %result2, align 1
+  %result3 = load i1, i1* %result2, align 1
+  br i1 %result3, label %then, label %else
+
+then:                                             ; preds = %entry
+  %what4 = load i8*, i8** %what1, align 8
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([10 x i8], [10 x i8]* @strtmp, i32 0, i32 0), i8* %what4)
+  br label %exit
+
+else:                                             ; preds = %entry
+  %what5 = load i8*, i8** %what1, align 8
+  %1 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.1, i32 0, i32 0), i8* %what5)
+  br label %exit
+
+exit:                                             ; preds = %else, %then
+  ret void
+}
+
+define i32 @main() {
+entry:
+  %ab = alloca i8*, align 8
+  %concattmp = call i8* @novum.strconcat(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.2, i32 0, i32 0), i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.3, i32 0, i32 0))
+  store i8* %concattmp, i8** %ab, align 8
+  %ab1 = load i8*, i8** %ab, align 8
+  %strcmptmp = call i32 @strcmp(i8* %ab1, i8* getelementptr inbounds ([3 x i8], [3 x i8]* @strtmp.5, i32 0, i32 0))
+  %cmptmp = icmp eq i32 %strcmptmp, 0
+  call void @show(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.4, i32 0, i32 0), i1 %cmptmp)
+  %ab2 = load i8*, i8** %ab, align 8
+  %strcmptmp3 = call i32 @strcmp(i8* %ab2, i8* getelementptr inbounds ([3 x i8], [3 x i8]* @strtmp.7, i32 0, i32 0))
+  %cmptmp4 = icmp ne i32 %strcmptmp3, 0
+  call void @show(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.6, i32 0, i32 0), i1 %cmptmp4)
+  %strcmptmp5 = call i32 @strcmp(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.9, i32 0, i32 0), i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.10, i32 0, i32 0))
+  %cmptmp6 = icmp slt i32 %strcmptmp5, 0
+  call void @show(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.8, i32 0, i32 0), i1 %cmptmp6)
+  %strcmptmp7 = call i32 @strcmp(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.12, i32 0, i32 0), i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.13, i32 0, i32 0))
+  %cmptmp8 = icmp sle i32 %strcmptmp7, 0
+  call void @show(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.11, i32 0, i32 0), i1 %cmptmp8)
+  %strcmptmp9 = call i32 @strcmp(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.15, i32 0, i32 0), i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.16, i32 0, i32 0))
+  %cmptmp10 = icmp sgt i32 %strcmptmp9, 0
+  call void @show(i8* getelementptr inbounds ([10 x i8], [10 x i8]* @strtmp.14, i32 0, i32 0), i1 %cmptmp10)
+  %strcmptmp11 = call i32 @strcmp(i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.18, i32 0, i32 0), i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.19, i32 0, i32 0))
+  %cmptmp12 = icmp sge i32 %strcmptmp11, 0
+  call void @show(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.17, i32 0, i32 0), i1 %cmptmp12)
+  %ab13 = load i8*, i8** %ab, align 8
+  %identicaltmp = icmp eq i8* %ab13, getelementptr inbounds ([3 x i8], [3 x i8]* @strtmp.21, i32 0, i32 0)
+  call void @show(i8* getelementptr inbounds ([20 x i8], [20 x i8]* @strtmp.20, i32 0, i32 0), i1 %identicaltmp)
+  %ab14 = load i8*, i8** %ab, align 8
+  %ab15 = load i8*, i8** %ab, align 8
+  %identicaltmp16 = icmp eq i8* %ab14, %ab15
+  call void @show(i8* getelementptr inbounds ([18 x i8], [18 x i8]* @strtmp.22, i32 0, i32 0), i1 %identicaltmp16)
+  ret i32 0
+}
+
+declare i64 @strlen(i8*)
+
+declare i8* @malloc(i64)
+
+declare i8* @memcpy(i8*, i8*, i64)
+
+define internal i8* @novum.strconcat(i8* %a, i8* %b) {
+entry:
+  %lena = call i64 @strlen(i8* %a)
+  %lenb = call i64 @strlen(i8* %b)
+  %lenbnull = add i64 %lenb, 1
+  %size = add i64 %lena, %lenbnull
+  %buffer = call i8* @malloc(i64 %size)
+  %0 = call i8* @memcpy(i8* %buffer, i8* %a, i64 %lena)
+  %tail = getelementptr inbounds i8, i8* %buffer, i64 %lena
+  %1 = call i8* @memcpy(i8* %tail, i8* %b, i64 %lenbnull)
+  ret i8* %buffer
+}
+
+declare i32 @strcmp(i8*, i8*)
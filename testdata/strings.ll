@@ -0,0 +1,131 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@strtmp = private unnamed_addr constant [8 x i8] c"Hello, \00", align 1
+@strtmp.1 = private unnamed_addr constant [2 x i8] c"!\00", align 1
+@strtmp.2 = private unnamed_addr constant [1 x i8] zeroinitializer, align 1
+@strtmp.3 = private unnamed_addr constant [3 x i8] c"ab\00", align 1
+@strtmp.4 = private unnamed_addr constant [4 x i8] c"%s\0A\00", align 1
+@strtmp.5 = private unnamed_addr constant [1 x i8] zeroinitializer, align 1
+@strtmp.6 = private unnamed_addr constant [2 x i8] c"c\00", align 1
+@strtmp.7 = private unnamed_addr constant [4 x i8] c"%s\0A\00", align 1
+@strtmp.8 = private unnamed_addr constant [4 x i8] c"%s\0A\00", align 1
+@strtmp.9 = private unnamed_addr constant [6 x i8] c"novum\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define i8* @greet(i8* %name) {
+entry:
+  %name1 = alloca i8*, align 8
+  store i8* %name, i8** %name1, align 8
+  %name2 = load i8*, i8** %name1, align 8
+  %concattmp = call i8* @novum.strconcat(i8* getelementptr inbounds ([8 x i8], [8 x i8]* @strtmp, i32 0, i32 0), i8* %name2)
+  %concattmp3 = call i8* @novum.strconcat(i8* %concattmp, i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.1, i32 0, i32 0))
+  ret i8* %concattmp3
+}
+
+define i32 @main() {
+entry:
+  %c = alloca i8, align 1
+  %ind10 = alloca i32, align 4
+  %doubled = alloca i8*, align 8
+  %i = alloca i32, align 4
+  %ind = alloca i32, align 4
+  %line = alloca i8*, align 8
+  store i8* getelementptr inbounds ([1 x i8], [1 x i8]* @strtmp.2, i32 0, i32 0), i8** %line, align 8
+  store i32 0, i32* %ind, align 4
+  store i32 0, i32* %i, align 4
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %i1 = load i32, i32* %i, align 4
+  %beforeend = icmp slt i32 %i1, 3
+  %afterend = icmp sgt i32 %i1, 3
+  %loopcond = select i1 true, i1 %beforeend, i1 %afterend
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %line2 = load i8*, i8** %line, align 8
+  %concattmp = call i8* @novum.strconcat(i8* %line2, i8* getelementptr inbounds ([3 x i8], [3 x i8]* @strtmp.3, i32 0, i32 0))
+  store i8* %concattmp, i8** %line, align 8
+  br label %looplatch
+
+looplatch:                                        ; preds = %loop
+  %i3 = load i32, i32* %i, align 4
+  %next = add i32 %i3, 1
+  store i32 %next, i32* %i, align 4
+  %ind4 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind4, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  %line5 = load i8*, i8** %line, align 8
+  %0 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.4, i32 0, i32 0), i8* %line5)
+  store i8* getelementptr inbounds ([1 x i8], [1 x i8]* @strtmp.5, i32 0, i32 0), i8** %doubled, align 8
+  store i32 0, i32* %ind10, align 4
+  %line11 = load i8*, i8** %line, align 8
+  %concattmp12 = call i8* @novum.strconcat(i8* %line11, i8* getelementptr inbounds ([2 x i8], [2 x i8]* @strtmp.6, i32 0, i32 0))
+  %len = call i64 @strlen(i8* %concattmp12)
+  %len13 = trunc i64 %len to i32
+  br label %loopheader6
+
+loopheader6:                                      ; preds = %looplatch8, %exitloop
+  %ind14 = load i32, i32* %ind10, align 4
+  %loopcond15 = icmp slt i32 %ind14, %len13
+  br i1 %loopcond15, label %loop7, label %exitloop9
+
+loop7:                                            ; preds = %loopheader6
+  %1 = getelementptr inbounds i8, i8* %concattmp12, i32 %ind14
+  %char = load i8, i8* %1, align 1
+  store i8 %char, i8* %c, align 1
+  %doubled16 = load i8*, i8** %doubled, align 8
+  %c17 = load i8, i8* %c, align 1
+  %strtmp = call i8* @malloc(i64 2)
+  store i8 %c17, i8* %strtmp, align 1
+  %strend = getelementptr inbounds i8, i8* %strtmp, i64 1
+  store i8 0, i8* %strend, align 1
+  %concattmp18 = call i8* @novum.strconcat(i8* %doubled16, i8* %strtmp)
+  %c19 = load i8, i8* %c, align 1
+  %strtmp20 = call i8* @malloc(i64 2)
+  store i8 %c19, i8* %strtmp20, align 1
+  %strend21 = getelementptr inbounds i8, i8* %strtmp20, i64 1
+  store i8 0, i8* %strend21, align 1
+  %concattmp22 = call i8* @novum.strconcat(i8* %concattmp18, i8* %strtmp20)
+  store i8* %concattmp22, i8** %doubled, align 8
+  br label %looplatch8
+
+looplatch8:                                       ; preds = %loop7
+  %ind23 = load i32, i32* %ind10, align 4
+  %nextind24 = add i32 %ind23, 1
+  store i32 %nextind24, i32* %ind10, align 4
+  br label %loopheader6
+
+exitloop9:                                        ; preds = %loopheader6
+  %doubled25 = load i8*, i8** %doubled, align 8
+  %2 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.7, i32 0, i32 0), i8* %doubled25)
+  %3 = call i8* @greet(i8* getelementptr inbounds ([6 x i8], [6 x i8]* @strtmp.9, i32 0, i32 0))
+  %4 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @strtmp.8, i32 0, i32 0), i8* %3)
+  ret i32 0
+}
+
+declare i64 @strlen(i8*)
+
+declare i8* @malloc(i64)
+
+declare i8* @memcpy(i8*, i8*, i64)
+
+define internal i8* @novum.strconcat(i8* %a, i8* %b) {
+entry:
+  %lena = call i64 @strlen(i8* %a)
+  %lenb = call i64 @strlen(i8* %b)
+  %lenbnull = add i64 %lenb, 1
+  %size = add i64 %lena, %lenbnull
+  %buffer = call i8* @malloc(i64 %size)
+  %0 = call i8* @memcpy(i8* %buffer, i8* %a, i64 %lena)
+  %tail = getelementptr inbounds i8, i8* %buffer, i64 %lena
+  %1 = call i8* @memcpy(i8* %tail, i8* %b, i64 %lenbnull)
+  ret i8* %buffer
+}
@@ -0,0 +1,204 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+%struct.Point = type { double, double }
+%struct.Segment = type { %struct.Point, %struct.Point, i8* }
+%struct.Path = type { { %struct.Point*, i32 } }
+
+@0 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@1 = private unnamed_addr constant [48 x i8] c"Panic: right side of the equation is equal to 0\00", align 1
+@strtmp = private unnamed_addr constant [9 x i8] c"diagonal\00", align 1
+@strtmp.1 = private unnamed_addr constant [12 x i8] c"mid x %.1f\0A\00", align 1
+@strtmp.2 = private unnamed_addr constant [12 x i8] c"mid y %.1f\0A\00", align 1
+@strtmp.3 = private unnamed_addr constant [11 x i8] c"to y %.1f\0A\00", align 1
+@strtmp.4 = private unnamed_addr constant [24 x i8] c"origin after move %.1f\0A\00", align 1
+@strtmp.5 = private unnamed_addr constant [15 x i8] c"origin x %.1f\0A\00", align 1
+@2 = private unnamed_addr constant [46 x i8] c"Panic: index out of range at structs.nv:37:16\00", align 1
+@strtmp.6 = private unnamed_addr constant [14 x i8] c"point y %.1f\0A\00", align 1
+@strtmp.7 = private unnamed_addr constant [25 x i8] c"right of origin by %.1f\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define %struct.Point @midpoint(%struct.Segment %s) {
+entry:
+  %s1 = alloca %struct.Segment, align 8
+  store %struct.Segment %s, %struct.Segment* %s1, align 8
+  %s2 = load %struct.Segment, %struct.Segment* %s1, align 8
+  %from = extractvalue %struct.Segment %s2, 0
+  %x = extractvalue %struct.Point %from, 0
+  %s3 = load %struct.Segment, %struct.Segment* %s1, align 8
+  %to = extractvalue %struct.Segment %s3, 1
+  %x4 = extractvalue %struct.Point %to, 0
+  %addtmp = fadd double %x, %x4
+  br i1 false, label %panic, label %ok
+
+panic:                                            ; preds = %entry
+  %0 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @0, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %entry
+  %divtmp = fdiv double %addtmp, 2.000000e+00
+  %1 = insertvalue %struct.Point undef, double %divtmp, 0
+  %s5 = load %struct.Segment, %struct.Segment* %s1, align 8
+  %from6 = extractvalue %struct.Segment %s5, 0
+  %y = extractvalue %struct.Point %from6, 1
+  %s7 = load %struct.Segment, %struct.Segment* %s1, align 8
+  %to8 = extractvalue %struct.Segment %s7, 1
+  %y9 = extractvalue %struct.Point %to8, 1
+  %addtmp10 = fadd double %y, %y9
+  br i1 false, label %panic11, label %ok12
+
+panic11:                                          ; preds = %ok
+  %2 = call i32 @puts(i8* getelementptr inbounds ([48 x i8], [48 x i8]* @1, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok12:                                             ; preds = %ok
+  %divtmp13 = fdiv double %addtmp10, 2.000000e+00
+  %3 = insertvalue %struct.Point %1, double %divtmp13, 1
+  ret %struct.Point %3
+}
+
+define %struct.Point @moved(%struct.Point %p, double %dx) {
+entry:
+  %q = alloca %struct.Point, align 8
+  %dx2 = alloca double, align 8
+  %p1 = alloca %struct.Point, align 8
+  store %struct.Point %p, %struct.Point* %p1, align 8
+  store double %dx, double* %dx2, align 8
+  %p3 = load %struct.Point, %struct.Point* %p1, align 8
+  store %struct.Point %p3, %struct.Point* %q, align 8
+  %q4 = load %struct.Point, %struct.Point* %q, align 8
+  %x = extractvalue %struct.Point %q4, 0
+  %dx5 = load double, double* %dx2, align 8
+  %addtmp = fadd double %x, %dx5
+  %x6 = getelementptr inbounds %struct.Point, %struct.Point* %q, i32 0, i32 0
+  store double %addtmp, double* %x6, align 8
+  %q7 = load %struct.Point, %struct.Point* %q, align 8
+  ret %struct.Point %q7
+}
+
+define i32 @main() {
+entry:
+  %p = alloca %struct.Point, align 8
+  %ind = alloca i32, align 4
+  %path = alloca %struct.Path, align 8
+  %mid = alloca %struct.Point, align 8
+  %s = alloca %struct.Segment, align 8
+  %origin = alloca %struct.Point, align 8
+  store %struct.Point zeroinitializer, %struct.Point* %origin, align 8
+  %origin1 = load %struct.Point, %struct.Point* %origin, align 8
+  %0 = insertvalue %struct.Segment undef, %struct.Point %origin1, 0
+  %1 = insertvalue %struct.Segment %0, %struct.Point { double 4.000000e+00, double 2.000000e+00 }, 1
+  %2 = insertvalue %struct.Segment %1, i8* getelementptr inbounds ([9 x i8], [9 x i8]* @strtmp, i32 0, i32 0), 2
+  store %struct.Segment %2, %struct.Segment* %s, align 8
+  %s2 = load %struct.Segment, %struct.Segment* %s, align 8
+  %3 = call %struct.Point @midpoint(%struct.Segment %s2)
+  store %struct.Point %3, %struct.Point* %mid, align 8
+  %mid3 = load %struct.Point, %struct.Point* %mid, align 8
+  %x = extractvalue %struct.Point %mid3, 0
+  %4 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([12 x i8], [12 x i8]* @strtmp.1, i32 0, i32 0), double %x)
+  %mid4 = load %struct.Point, %struct.Point* %mid, align 8
+  %y = extractvalue %struct.Point %mid4, 1
+  %5 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([12 x i8], [12 x i8]* @strtmp.2, i32 0, i32 0), double %y)
+  %to = getelementptr inbounds %struct.Segment, %struct.Segment* %s, i32 0, i32 1
+  %y5 = getelementptr inbounds %struct.Point, %struct.Point* %to, i32 0, i32 1
+  store double 8.000000e+00, double* %y5, align 8
+  %s6 = load %struct.Segment, %struct.Segment* %s, align 8
+  %to7 = extractvalue %struct.Segment %s6, 1
+  %y8 = extractvalue %struct.Point %to7, 1
+  %6 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.3, i32 0, i32 0), double %y8)
+  %origin9 = load %struct.Point, %struct.Point* %origin, align 8
+  %7 = call %struct.Point @moved(%struct.Point %origin9, double 3.000000e+00)
+  %x10 = extractvalue %struct.Point %7, 0
+  %8 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([24 x i8], [24 x i8]* @strtmp.4, i32 0, i32 0), double %x10)
+  %origin11 = load %struct.Point, %struct.Point* %origin, align 8
+  %x12 = extractvalue %struct.Point %origin11, 0
+  %9 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([15 x i8], [15 x i8]* @strtmp.5, i32 0, i32 0), double %x12)
+  %10 = call i8* @malloc(i64 32)
+  %elements = bitcast i8* %10 to %struct.Point*
+  %11 = getelementptr inbounds %struct.Point, %struct.Point* %elements, i32 0
+  %origin13 = load %struct.Point, %struct.Point* %origin, align 8
+  store %struct.Point %origin13, %struct.Point* %11, align 8
+  %12 = getelementptr inbounds %struct.Point, %struct.Point* %elements, i32 1
+  %mid14 = load %struct.Point, %struct.Point* %mid, align 8
+  store %struct.Point %mid14, %struct.Point* %12, align 8
+  %13 = insertvalue { %struct.Point*, i32 } undef, %struct.Point* %elements, 0
+  %array = insertvalue { %struct.Point*, i32 } %13, i32 2, 1
+  %14 = insertvalue %struct.Path undef, { %struct.Point*, i32 } %array, 0
+  store %struct.Path %14, %struct.Path* %path, align 8
+  %path15 = load %struct.Path, %struct.Path* %path, align 8
+  %points = extractvalue %struct.Path %path15, 0
+  %len = extractvalue { %struct.Point*, i32 } %points, 1
+  %outofrange = icmp uge i32 1, %len
+  br i1 %outofrange, label %panic, label %ok
+
+panic:                                            ; preds = %entry
+  %15 = call i32 @puts(i8* getelementptr inbounds ([46 x i8], [46 x i8]* @2, i32 0, i32 0))
+  call void @exit(i32 0)
+  unreachable
+
+ok:                                               ; preds = %entry
+  %elements16 = extractvalue { %struct.Point*, i32 } %points, 0
+  %16 = getelementptr inbounds %struct.Point, %struct.Point* %elements16, i32 1
+  %y17 = getelementptr inbounds %struct.Point, %struct.Point* %16, i32 0, i32 1
+  store double 5.000000e+00, double* %y17, align 8
+  store i32 0, i32* %ind, align 4
+  %path18 = load %struct.Path, %struct.Path* %path, align 8
+  %points19 = extractvalue %struct.Path %path18, 0
+  %elements20 = extractvalue { %struct.Point*, i32 } %points19, 0
+  %len21 = extractvalue { %struct.Point*, i32 } %points19, 1
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %ok
+  %ind22 = load i32, i32* %ind, align 4
+  %loopcond = icmp slt i32 %ind22, %len21
+  br i1 %loopcond, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %17 = getelementptr inbounds %struct.Point, %struct.Point* %elements20, i32 %ind22
+  %elem = load %struct.Point, %struct.Point* %17, align 8
+  store %struct.Point %elem, %struct.Point* %p, align 8
+  %p23 = load %struct.Point, %struct.Point* %p, align 8
+  %y24 = extractvalue %struct.Point %p23, 1
+  %18 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([14 x i8], [14 x i8]* @strtmp.6, i32 0, i32 0), double %y24)
+  br label %looplatch
+
+looplatch:                                        ; preds = %loop
+  %ind25 = load i32, i32* %ind, align 4
+  %nextind = add i32 %ind25, 1
+  store i32 %nextind, i32* %ind, align 4
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  %mid26 = load %struct.Point, %struct.Point* %mid, align 8
+  %x27 = extractvalue %struct.Point %mid26, 0
+  %origin28 = load %struct.Point, %struct.Point* %origin, align 8
+  %x29 = extractvalue %struct.Point %origin28, 0
+  %cmptmp = fcmp ogt double %x27, %x29
+  br i1 %cmptmp, label %then, label %else
+
+then:                                             ; preds = %exitloop
+  %mid30 = load %struct.Point, %struct.Point* %mid, align 8
+  %x31 = extractvalue %struct.Point %mid30, 0
+  %origin32 = load %struct.Point, %struct.Point* %origin, align 8
+  %x33 = extractvalue %struct.Point %origin32, 0
+  %subtmp = fsub double %x31, %x33
+  %19 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([25 x i8], [25 x i8]* @strtmp.7, i32 0, i32 0), double %subtmp)
+  br label %exit
+
+else:                                             ; preds = %exitloop
+  br label %exit
+
+exit:                                             ; preds = %else, %then
+  ret i32 0
+}
+
+declare i32 @puts(i8*)
+
+declare void @exit(i32)
+
+declare i8* @malloc(i64)
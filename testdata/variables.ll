@@ -0,0 +1,72 @@
+; ModuleID = 'novumroot'
+source_filename = "novumroot"
+target datalayout = "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu"
+
+@strtmp = private unnamed_addr constant [9 x i8] c"sum: %d\0A\00", align 1
+@strtmp.1 = private unnamed_addr constant [11 x i8] c"inner: %d\0A\00", align 1
+@strtmp.2 = private unnamed_addr constant [11 x i8] c"outer: %d\0A\00", align 1
+
+declare i32 @printf(i8*, ...)
+
+define i32 @sum(i32 %n) {
+entry:
+  %i = alloca i32, align 4
+  %total = alloca i32, align 4
+  %n1 = alloca i32, align 4
+  store i32 %n, i32* %n1, align 4
+  store i32 0, i32* %total, align 4
+  store i32 1, i32* %i, align 4
+  br label %loopheader
+
+loopheader:                                       ; preds = %looplatch, %entry
+  %i2 = load i32, i32* %i, align 4
+  %n3 = load i32, i32* %n1, align 4
+  %addtmp = add i32 %n3, 1
+  %cmptmp = icmp slt i32 %i2, %addtmp
+  br i1 %cmptmp, label %loop, label %exitloop
+
+loop:                                             ; preds = %loopheader
+  %total4 = load i32, i32* %total, align 4
+  %i5 = load i32, i32* %i, align 4
+  %addtmp6 = add i32 %total4, %i5
+  store i32 %addtmp6, i32* %total, align 4
+  %i7 = load i32, i32* %i, align 4
+  %addtmp8 = add i32 %i7, 1
+  store i32 %addtmp8, i32* %i, align 4
+  br label %looplatch
+
+looplatch:                                        ; preds = %loop
+  br label %loopheader
+
+exitloop:                                         ; preds = %loopheader
+  %total9 = load i32, i32* %total, align 4
+  ret i32 %total9
+}
+
+define i32 @main() {
+entry:
+  %x2 = alloca i32, align 4
+  %x = alloca i32, align 4
+  %limit = alloca i32, align 4
+  store i32 10, i32* %limit, align 4
+  %limit1 = load i32, i32* %limit, align 4
+  %0 = call i32 @sum(i32 %limit1)
+  %1 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([9 x i8], [9 x i8]* @strtmp, i32 0, i32 0), i32 %0)
+  store i32 1, i32* %x, align 4
+  br i1 true, label %then, label %else
+
+then:                                             ; preds = %entry
+  store i32 2, i32* %x2, align 4
+  %x3 = load i32, i32* %x2, align 4
+  %2 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.1, i32 0, i32 0), i32 %x3)
+  br label %exit
+
+else:                                             ; preds = %entry
+  br label %exit
+
+exit:                                             ; preds = %else, %then
+  %x4 = load i32, i32* %x, align 4
+  %3 = call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([11 x i8], [11 x i8]* @strtmp.2, i32 0, i32 0), i32 %x4)
+  ret i32 0
+}